@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"monitoring/config"
+	"monitoring/pkg/jwtkeys"
 	"monitoring/pkg/postgres"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	// "monitoring/internal/delivery/cron"
+	"monitoring/internal/delivery/cron"
 	rest "monitoring/internal/delivery/rest"
 	"monitoring/internal/delivery/rest/middlewares"
 	. "monitoring/internal/globals"
 	"monitoring/internal/util/midlog"
 
+	"github.com/golang-jwt/jwt/v5"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/joho/godotenv"
@@ -46,21 +54,37 @@ func main() {
 		midlog.FatalF("Error creating admin user: %v", err)
 	}
 
-	// cronJob, err := cron.New()
-	// if err != nil {
-	// 	midlog.FatalF("Error creating cron job: %v", err)
-	// }
-	// cronJob.Start()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
 
-	r, err := rest.New()
+	cronJob, err := cron.New()
 	if err != nil {
-		midlog.FatalF("Error creating rest server: %v", err)
+		midlog.FatalF("Error creating cron job: %v", err)
 	}
-	err = r.Start(GlobalConfig.HTTP.Address)
+	cronJob.Start()
+
+	r, err := rest.New()
 	if err != nil {
-		midlog.FatalF("Error starting rest server: %v", err)
+		midlog.FatalF("Error creating rest server: %v", err)
 	}
+	go func() {
+		err := r.Start(GlobalConfig.HTTP.Address)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			midlog.FatalF("Error starting rest server: %v", err)
+		}
+	}()
 
+	<-ctx.Done()
+	midlog.InfoF("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := r.Shutdown(shutdownCtx); err != nil {
+		midlog.ErrorEF(err, "Error shutting down rest server")
+	}
+	if err := cronJob.Stop(shutdownCtx); err != nil {
+		midlog.ErrorEF(err, "Error stopping cron job")
+	}
 }
 
 func SetGlobals() {
@@ -71,7 +95,29 @@ func SetGlobals() {
 		midlog.FatalF("Error creating monitoring postgres client: %v", err)
 	}
 
+	GlobalJWTKeys, err = jwtKeys(GlobalConfig.JWT)
+	if err != nil {
+		midlog.FatalF("Error loading jwt keys: %v", err)
+	}
+
 	GlobalConfig.HTTP.Address = "127.0.0.1:8090"
 	GlobalConfig.HTTP.Debug = true
 
 }
+
+func jwtKeys(cfg config.JWTConfig) (*jwtkeys.KeySet, error) {
+	switch {
+	case len(cfg.Keys) > 0:
+		return jwtkeys.Parse(cfg.KeyID, cfg.Keys)
+	case cfg.Secret != "":
+		return jwtkeys.New("default", jwtkeys.Key{
+			ID:        "default",
+			Method:    jwt.SigningMethodHS256,
+			SignKey:   []byte(cfg.Secret),
+			VerifyKey: []byte(cfg.Secret),
+		})
+	}
+
+	midlog.Warn("No jwt key configured, using a random key, tokens won't survive a restart")
+	return jwtkeys.Ephemeral()
+}
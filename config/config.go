@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -17,11 +18,84 @@ type (
 		HTTP     HTTPConf
 		PGConn   string
 		Cron     CronConfig
+		Probe    ProbeConfig
+		Alert    AlertConfig
+		Status   StatusPageConfig
+		Metrics  MetricsConfig
+		JWT      JWTConfig
+		OIDC     OIDCConfig
 		LogLevel string
 	}
 
 	CronConfig struct {
+		// default schedule of services without their own interval
 		CPUInterval string
+		// random delay added to every run, e.g. "5s"
+		Jitter string
+		// how often the service list is reloaded, e.g. "1m"
+		Refresh string
+	}
+
+	ProbeConfig struct {
+		// number of checks the rolling error estimate averages over
+		ErrorWindow int
+		// days before certificate expiry to report, e.g. "30,14,7,1"
+		CertWarnDays []int
+	}
+
+	StatusPageConfig struct {
+		// serves the public /status page when set
+		Enabled bool
+		Title   string `default:"Service Status"`
+	}
+
+	MetricsConfig struct {
+		// serves Prometheus metrics on /metrics to callers with system:read,
+		// e.g. an api key sent as "Authorization: ApiKey <key>"
+		Enabled bool
+	}
+
+	JWTConfig struct {
+		// HS256 secret used as key "default" when Keys is empty
+		Secret string
+		// id of the key new tokens are signed with
+		KeyID string
+		// keys as "kid:alg:value", the value is the secret for HS256 and
+		// the path of a PEM file for RS256 and EdDSA
+		Keys []string
+		// lifetime of access and refresh tokens, e.g. "15m" and "720h"
+		AccessTTL  time.Duration
+		RefreshTTL time.Duration
+	}
+
+	OIDCConfig struct {
+		// serves /oidc/login and /oidc/callback when set
+		Enabled      bool
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		// must point at /oidc/callback and be registered with the provider
+		RedirectURL string
+		Scopes      []string `default:"openid,profile,email"`
+		// ID token claim holding the username of new users
+		UsernameClaim string `default:"preferred_username"`
+		// ID token claim, a string or a list, matched against RoleMapping
+		RoleClaim string `default:"groups"`
+		// claim values as "value:roleid", e.g. "ops-admins:1"
+		RoleMapping []string
+		// role of users no mapping matches, 0 denies them
+		DefaultRole int
+	}
+
+	AlertConfig struct {
+		WebhookURL   string
+		SlackURL     string
+		SlackChannel string
+		SMTPAddr     string
+		SMTPFrom     string
+		SMTPTo       []string
+		SMTPUsername string
+		SMTPPassword string
 	}
 )
 
@@ -0,0 +1,4 @@
+SET search_path TO monitoring, public;
+
+-- cron expression ("*/5 * * * *") or duration ("30s"), NULL uses the default
+ALTER TABLE services ADD COLUMN IF NOT EXISTS check_interval VARCHAR(255);
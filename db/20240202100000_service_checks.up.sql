@@ -0,0 +1,20 @@
+SET search_path TO monitoring, public;
+
+CREATE TABLE IF NOT EXISTS service_checks (
+    id BIGSERIAL PRIMARY KEY,
+    service_id INTEGER NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    checked_at TIMESTAMPTZ NOT NULL,
+    success BOOLEAN NOT NULL,
+    status_code INTEGER,
+    latency_ms BIGINT NOT NULL,
+    response_size BIGINT,
+    error TEXT
+);
+
+-- range queries are always "one service, between two timestamps"
+CREATE INDEX IF NOT EXISTS service_checks_service_id_checked_at_idx
+    ON service_checks (service_id, checked_at DESC);
+
+-- cheap index for retention jobs that scan by time only
+CREATE INDEX IF NOT EXISTS service_checks_checked_at_brin_idx
+    ON service_checks USING BRIN (checked_at);
@@ -0,0 +1,3 @@
+SET search_path TO monitoring, public;
+
+DROP TABLE IF EXISTS error_reports;
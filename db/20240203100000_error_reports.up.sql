@@ -0,0 +1,15 @@
+SET search_path TO monitoring, public;
+
+CREATE TABLE IF NOT EXISTS error_reports (
+    id BIGSERIAL PRIMARY KEY,
+    service_id INTEGER REFERENCES services(id) ON DELETE SET NULL,
+    service_name VARCHAR(255) NOT NULL,
+    log TEXT NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS error_reports_service_name_occurred_at_idx
+    ON error_reports (service_name, occurred_at DESC);
+
+CREATE INDEX IF NOT EXISTS error_reports_occurred_at_idx
+    ON error_reports (occurred_at DESC);
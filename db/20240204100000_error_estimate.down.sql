@@ -0,0 +1,3 @@
+SET search_path TO monitoring, public;
+
+ALTER TABLE services DROP COLUMN IF EXISTS error_estimate;
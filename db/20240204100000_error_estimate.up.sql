@@ -0,0 +1,9 @@
+SET search_path TO monitoring, public;
+
+-- rolling error rate in percent, maintained by the probe engine
+ALTER TABLE services ADD COLUMN IF NOT EXISTS error_estimate DOUBLE PRECISION NOT NULL DEFAULT 0;
+-- the column may predate this migration as a nullable one of another type
+ALTER TABLE services ALTER COLUMN error_estimate TYPE DOUBLE PRECISION;
+UPDATE services SET error_estimate = 0 WHERE error_estimate IS NULL;
+ALTER TABLE services ALTER COLUMN error_estimate SET DEFAULT 0;
+ALTER TABLE services ALTER COLUMN error_estimate SET NOT NULL;
@@ -0,0 +1,4 @@
+SET search_path TO monitoring, public;
+
+ALTER TABLE services DROP COLUMN IF EXISTS check_config;
+ALTER TABLE services DROP COLUMN IF EXISTS check_type;
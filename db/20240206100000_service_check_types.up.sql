@@ -0,0 +1,4 @@
+SET search_path TO monitoring, public;
+
+ALTER TABLE services ADD COLUMN IF NOT EXISTS check_type VARCHAR(16) NOT NULL DEFAULT 'http';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS check_config JSONB;
@@ -0,0 +1,16 @@
+SET search_path TO monitoring, public;
+
+-- latest certificate seen for every service checked over TLS
+CREATE TABLE IF NOT EXISTS service_certificates (
+    service_id INTEGER PRIMARY KEY REFERENCES services(id) ON DELETE CASCADE,
+    subject TEXT NOT NULL,
+    issuer TEXT NOT NULL,
+    sans TEXT[] NOT NULL DEFAULT '{}',
+    not_before TIMESTAMPTZ NOT NULL,
+    not_after TIMESTAMPTZ NOT NULL,
+    hostname_match BOOLEAN NOT NULL,
+    checked_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS service_certificates_not_after_idx
+    ON service_certificates (not_after);
@@ -0,0 +1,15 @@
+SET search_path TO monitoring, public;
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id SERIAL PRIMARY KEY,
+    service_id INTEGER NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    window_seconds INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- whether the condition currently holds, so recoveries survive restarts
+    firing BOOLEAN NOT NULL DEFAULT FALSE,
+    changed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS alert_rules_service_id_idx ON alert_rules (service_id);
@@ -0,0 +1,5 @@
+SET search_path TO monitoring, public;
+
+ALTER TABLE error_reports DROP COLUMN IF EXISTS incident_id;
+DROP TABLE IF EXISTS incident_events;
+DROP TABLE IF EXISTS incidents;
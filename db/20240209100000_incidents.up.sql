@@ -0,0 +1,35 @@
+SET search_path TO monitoring, public;
+
+CREATE TABLE IF NOT EXISTS incidents (
+    id BIGSERIAL PRIMARY KEY,
+    service_id INTEGER NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    title TEXT NOT NULL,
+    opened_at TIMESTAMPTZ NOT NULL,
+    acknowledged_at TIMESTAMPTZ,
+    acknowledged_by TEXT,
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS incidents_service_id_opened_at_idx
+    ON incidents (service_id, opened_at DESC);
+
+-- at most one unresolved incident per service
+CREATE UNIQUE INDEX IF NOT EXISTS incidents_service_id_unresolved_idx
+    ON incidents (service_id) WHERE status <> 'resolved';
+
+CREATE TABLE IF NOT EXISTS incident_events (
+    id BIGSERIAL PRIMARY KEY,
+    incident_id BIGINT NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    author TEXT,
+    message TEXT,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS incident_events_incident_id_created_at_idx
+    ON incident_events (incident_id, created_at);
+
+ALTER TABLE error_reports ADD COLUMN IF NOT EXISTS incident_id BIGINT REFERENCES incidents(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS error_reports_incident_id_idx ON error_reports (incident_id);
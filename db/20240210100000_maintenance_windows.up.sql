@@ -0,0 +1,15 @@
+SET search_path TO monitoring, public;
+
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id SERIAL PRIMARY KEY,
+    -- NULL applies the window to every service
+    service_id INTEGER REFERENCES services(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    recurrence VARCHAR(16) NOT NULL DEFAULT '',
+    until TIMESTAMPTZ,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS maintenance_windows_service_id_idx ON maintenance_windows (service_id);
@@ -0,0 +1,4 @@
+SET search_path TO monitoring, public;
+
+-- opt in to the public status page, only honoured for Demo access level services
+ALTER TABLE services ADD COLUMN IF NOT EXISTS public_status BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+SET search_path TO monitoring, public;
+
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,28 @@
+SET search_path TO monitoring, public;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    username TEXT NOT NULL,
+    -- sha256 of the token, the token itself is never stored
+    token_hash TEXT NOT NULL UNIQUE,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_username_idx ON refresh_tokens (username);
+
+-- access tokens revoked by jti, or every token of a user issued before revoked_before
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    jti TEXT,
+    username TEXT,
+    revoked_before TIMESTAMPTZ,
+    -- the row can be dropped once every token it matches has expired
+    expires_at TIMESTAMPTZ NOT NULL,
+    CHECK (jti IS NOT NULL OR (username IS NOT NULL AND revoked_before IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS revoked_tokens_jti_idx ON revoked_tokens (jti);
+CREATE INDEX IF NOT EXISTS revoked_tokens_username_idx ON revoked_tokens (username);
@@ -0,0 +1,12 @@
+SET search_path TO monitoring, public;
+
+-- keep the access users had through their teams
+INSERT INTO user_services (user_id, service_id)
+SELECT user_id, service_id FROM user_service_access
+EXCEPT
+SELECT user_id, service_id FROM user_services;
+
+DROP VIEW IF EXISTS user_service_access;
+DROP TABLE IF EXISTS team_services;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
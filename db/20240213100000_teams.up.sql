@@ -0,0 +1,37 @@
+SET search_path TO monitoring, public;
+
+-- user_services predates the migrations in this directory
+CREATE TABLE IF NOT EXISTS user_services (
+    service_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS teams (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS team_members_user_id_idx ON team_members (user_id);
+
+CREATE TABLE IF NOT EXISTS team_services (
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    service_id INTEGER NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, service_id)
+);
+
+-- services a user can access, assigned directly or through a team. Direct
+-- assignments stay in user_services and keep being managed there.
+CREATE OR REPLACE VIEW user_service_access AS
+    SELECT user_id, service_id FROM user_services
+    UNION
+    SELECT tm.user_id, ts.service_id
+    FROM team_members tm
+    JOIN team_services ts ON ts.team_id = tm.team_id;
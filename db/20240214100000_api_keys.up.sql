@@ -0,0 +1,21 @@
+SET search_path TO monitoring, public;
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    -- start of the key, shown to tell keys apart
+    prefix TEXT NOT NULL,
+    -- sha256 of the key, the key itself is never stored
+    key_hash TEXT NOT NULL UNIQUE,
+    -- the key acts for this user, limited to its scopes
+    user_id INTEGER NOT NULL,
+    username TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id);
@@ -0,0 +1,5 @@
+SET search_path TO monitoring, public;
+
+DROP INDEX IF EXISTS users_oidc_subject_idx;
+
+ALTER TABLE users DROP COLUMN IF EXISTS oidc_subject;
@@ -0,0 +1,7 @@
+SET search_path TO monitoring, public;
+
+-- subject of the OpenID Connect account a user logs in with
+ALTER TABLE users ADD COLUMN IF NOT EXISTS oidc_subject TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_oidc_subject_idx
+    ON users (oidc_subject) WHERE oidc_subject IS NOT NULL;
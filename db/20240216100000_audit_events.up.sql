@@ -0,0 +1,21 @@
+SET search_path TO monitoring, public;
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    -- the user that made the change, empty for changes made by the system
+    actor_id INTEGER NOT NULL DEFAULT 0,
+    actor TEXT NOT NULL DEFAULT '',
+    -- set when the change was made with an api key
+    api_key_id INTEGER,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target TEXT NOT NULL,
+    -- the fields that changed, before and after
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_created_at_idx ON audit_events (created_at DESC);
+CREATE INDEX IF NOT EXISTS audit_events_target_idx ON audit_events (target_type, target);
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events (actor);
@@ -0,0 +1,5 @@
+SET search_path TO monitoring, public;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_pkey;
+ALTER TABLE users ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE IF EXISTS users_id_seq;
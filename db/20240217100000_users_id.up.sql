@@ -0,0 +1,10 @@
+SET search_path TO monitoring, public;
+
+-- users.id had neither a default nor a key, so every insert that didn't
+-- pick an id failed and nothing kept ids unique
+CREATE SEQUENCE IF NOT EXISTS users_id_seq OWNED BY users.id;
+SELECT setval('users_id_seq', COALESCE((SELECT max(id) FROM users), 0) + 1, false);
+ALTER TABLE users ALTER COLUMN id SET DEFAULT nextval('users_id_seq');
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_pkey;
+ALTER TABLE users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
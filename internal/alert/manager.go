@@ -0,0 +1,216 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/util/midlog"
+	"sync"
+	"time"
+)
+
+var logger = midlog.LoggerForModule("alert")
+
+// Manager evaluates the alert rules of a service after every check and
+// notifies when a rule starts or stops firing. Services without rules fall
+// back to notifying whenever they change between up and down, where the
+// first check after start up only notifies when it's down. While the service
+// is in a maintenance window nothing starts firing, so a problem that outlasts
+// the window is notified once it ends. Resolutions are always notified.
+type Manager struct {
+	Notifiers          []Notifier
+	IAlertRulesRepo    repository.IAlertRulesRepository
+	IServiceChecksRepo repository.IServiceChecksRepository
+	IMaintenanceRepo   repository.IMaintenanceRepository
+
+	mu     sync.Mutex
+	states map[int]bool
+}
+
+// NewManager builds a manager with a notifier for every destination set in
+// the alert configuration.
+func NewManager() *Manager {
+	cfg := GlobalConfig.Alert
+
+	var notifiers []Notifier
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, &WebhookNotifier{URL: cfg.WebhookURL})
+	}
+	if cfg.SlackURL != "" {
+		notifiers = append(notifiers, &SlackNotifier{URL: cfg.SlackURL, Channel: cfg.SlackChannel})
+	}
+	if cfg.SMTPAddr != "" && len(cfg.SMTPTo) > 0 {
+		notifiers = append(notifiers, &SMTPNotifier{
+			Addr:     cfg.SMTPAddr,
+			From:     cfg.SMTPFrom,
+			To:       cfg.SMTPTo,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		})
+	}
+
+	return &Manager{
+		Notifiers:          notifiers,
+		IAlertRulesRepo:    &repository.AlertRulesRepository{DB: GlobalPG},
+		IServiceChecksRepo: &repository.ServiceChecksRepository{DB: GlobalPG},
+		IMaintenanceRepo:   &repository.MaintenanceRepository{DB: GlobalPG},
+	}
+}
+
+func (m *Manager) Record(ctx context.Context, service model.Service, result model.CheckResult) error {
+	var rules []model.AlertRule
+	if m.IAlertRulesRepo != nil {
+		all, err := m.IAlertRulesRepo.List(ctx, result.ServiceID)
+		if err != nil {
+			return err
+		}
+		for _, rule := range all {
+			if rule.Enabled {
+				rules = append(rules, rule)
+			} else if rule.Firing {
+				// disabled while firing, e.g. directly in the database
+				if err := m.ResolveRule(ctx, rule, "rule was disabled"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if len(rules) == 0 {
+		return m.recordTransition(ctx, result)
+	}
+
+	var errs []error
+	for _, rule := range rules {
+		if err := m.recordRule(ctx, rule, result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) recordRule(ctx context.Context, rule model.AlertRule, result model.CheckResult) error {
+	firing, message, err := evaluate(ctx, m.IServiceChecksRepo, rule, result)
+	if err != nil || firing == rule.Firing {
+		return err
+	}
+
+	n := Notification{
+		ServiceID:   result.ServiceID,
+		ServiceName: result.ServiceName,
+		Status:      StatusFiring,
+		Rule:        Describe(rule),
+		Message:     message,
+		OccurredAt:  result.CheckedAt,
+	}
+	if !firing {
+		n.Status = StatusResolved
+	}
+	if suppressed, err := m.suppressed(ctx, n); err != nil || suppressed {
+		return err
+	}
+
+	if err := m.IAlertRulesRepo.SetFiring(ctx, rule.ID, firing, result.CheckedAt); err != nil {
+		return err
+	}
+	return m.Notify(ctx, n)
+}
+
+// ResolveRule clears a firing rule and notifies it as resolved. It's used
+// when a rule is disabled, changed or deleted, as it may never be evaluated
+// as resolved then.
+func (m *Manager) ResolveRule(ctx context.Context, rule model.AlertRule, reason string) error {
+	if !rule.Firing {
+		return nil
+	}
+	now := time.Now()
+	if err := m.IAlertRulesRepo.SetFiring(ctx, rule.ID, false, now); err != nil {
+		return err
+	}
+	return m.Notify(ctx, Notification{
+		ServiceID:   rule.ServiceID,
+		ServiceName: rule.ServiceName,
+		Status:      StatusResolved,
+		Rule:        Describe(rule),
+		Message:     reason,
+		OccurredAt:  now,
+	})
+}
+
+func (m *Manager) recordTransition(ctx context.Context, result model.CheckResult) error {
+	m.mu.Lock()
+	if m.states == nil {
+		m.states = map[int]bool{}
+	}
+	wasUp, seen := m.states[result.ServiceID]
+	m.mu.Unlock()
+
+	if (!seen && result.Success) || (seen && wasUp == result.Success) {
+		m.setState(result.ServiceID, result.Success)
+		return nil
+	}
+
+	n := Notification{
+		ServiceID:   result.ServiceID,
+		ServiceName: result.ServiceName,
+		Status:      StatusFiring,
+		Message:     result.Error,
+		OccurredAt:  result.CheckedAt,
+	}
+	if result.Success {
+		n.Status = StatusResolved
+		n.Message = "service is up again"
+	}
+	// the service isn't down as far as alerting goes until it's notified
+	if suppressed, err := m.suppressed(ctx, n); err != nil || suppressed {
+		return err
+	}
+
+	m.setState(result.ServiceID, result.Success)
+	return m.Notify(ctx, n)
+}
+
+func (m *Manager) setState(serviceID int, up bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[serviceID] = up
+}
+
+// suppressed reports whether n starts firing while its service is in
+// maintenance. Resolutions aren't suppressed, their firing notification was
+// sent before the window started.
+func (m *Manager) suppressed(ctx context.Context, n Notification) (bool, error) {
+	if n.Status != StatusFiring || m.IMaintenanceRepo == nil {
+		return false, nil
+	}
+	windows, err := m.IMaintenanceRepo.ListForService(ctx, n.ServiceID)
+	if err != nil {
+		return false, err
+	}
+	for _, window := range windows {
+		if window.Active(n.OccurredAt) {
+			logger.InfoF("Suppressed during maintenance %q: %s", window.Title, n.Summary())
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Notify sends n to every notifier, a failing notifier doesn't stop the
+// others. Each gets DefaultNotifyTimeout, so a stuck destination can't hold
+// up the checks.
+func (m *Manager) Notify(ctx context.Context, n Notification) error {
+	logger.InfoF("%s", n.Summary())
+
+	var errs []error
+	for _, notifier := range m.Notifiers {
+		notifyCtx, cancel := context.WithTimeout(ctx, DefaultNotifyTimeout)
+		err := notifier.Notify(notifyCtx, n)
+		cancel()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,169 @@
+package alert
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"testing"
+	"time"
+)
+
+type fakeRules struct {
+	repository.IAlertRulesRepository
+	rules []model.AlertRule
+}
+
+func (f *fakeRules) List(ctx context.Context, serviceID int) ([]model.AlertRule, error) {
+	return f.rules, nil
+}
+
+func (f *fakeRules) SetFiring(ctx context.Context, id int, firing bool, at time.Time) error {
+	for i := range f.rules {
+		if f.rules[i].ID == id {
+			f.rules[i].Firing = firing
+		}
+	}
+	return nil
+}
+
+type fakeChecks struct {
+	repository.IServiceChecksRepository
+	total, failed int64
+}
+
+func (f *fakeChecks) CountFailures(ctx context.Context, serviceID int, from, to time.Time) (int64, int64, error) {
+	return f.total, f.failed, nil
+}
+
+type recordingNotifier struct{ sent []Notification }
+
+func (r *recordingNotifier) Notify(ctx context.Context, n Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func TestManagerResolvesDisabledFiringRule(t *testing.T) {
+	rules := &fakeRules{rules: []model.AlertRule{
+		{ID: 1, ServiceID: 1, ServiceName: "api", Kind: model.RuleErrorRate, Threshold: 10, Enabled: false, Firing: true},
+	}}
+	notifier := &recordingNotifier{}
+	m := &Manager{Notifiers: []Notifier{notifier}, IAlertRulesRepo: rules, IServiceChecksRepo: &fakeChecks{}}
+
+	result := model.CheckResult{ServiceID: 1, ServiceName: "api", Success: true, CheckedAt: time.Now()}
+	if err := m.Record(context.Background(), model.Service{}, result); err != nil {
+		t.Fatal(err)
+	}
+	if rules.rules[0].Firing {
+		t.Error("disabled rule still firing")
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].Status != StatusResolved {
+		t.Fatalf("sent %+v, want one resolve", notifier.sent)
+	}
+}
+
+func TestManagerKeepsStateWithoutData(t *testing.T) {
+	rules := &fakeRules{rules: []model.AlertRule{
+		{ID: 1, ServiceID: 1, ServiceName: "api", Kind: model.RuleErrorRate, Threshold: 10, Enabled: true, Firing: true},
+	}}
+	notifier := &recordingNotifier{}
+	checks := &fakeChecks{}
+	m := &Manager{Notifiers: []Notifier{notifier}, IAlertRulesRepo: rules, IServiceChecksRepo: checks}
+
+	result := model.CheckResult{ServiceID: 1, ServiceName: "api", CheckedAt: time.Now()}
+	if err := m.Record(context.Background(), model.Service{}, result); err != nil {
+		t.Fatal(err)
+	}
+	if !rules.rules[0].Firing || len(notifier.sent) != 0 {
+		t.Fatalf("window without checks changed the state, sent %+v", notifier.sent)
+	}
+
+	checks.total, checks.failed = 10, 0
+	if err := m.Record(context.Background(), model.Service{}, result); err != nil {
+		t.Fatal(err)
+	}
+	if rules.rules[0].Firing || len(notifier.sent) != 1 || notifier.sent[0].Message == "" {
+		t.Fatalf("rule should resolve with a message, sent %+v", notifier.sent)
+	}
+}
+
+type fakeMaintenance struct {
+	repository.IMaintenanceRepository
+	windows []model.MaintenanceWindow
+}
+
+func (f *fakeMaintenance) ListForService(ctx context.Context, serviceID int) ([]model.MaintenanceWindow, error) {
+	return f.windows, nil
+}
+
+func TestManagerFiresRuleAfterMaintenance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	rules := &fakeRules{rules: []model.AlertRule{
+		{ID: 1, ServiceID: 1, ServiceName: "api", Kind: model.RuleErrorRate, Threshold: 10, Enabled: true},
+	}}
+	notifier := &recordingNotifier{}
+	m := &Manager{
+		Notifiers:          []Notifier{notifier},
+		IAlertRulesRepo:    rules,
+		IServiceChecksRepo: &fakeChecks{total: 10, failed: 5},
+		IMaintenanceRepo: &fakeMaintenance{windows: []model.MaintenanceWindow{
+			{Title: "upgrade", StartsAt: start, EndsAt: start.Add(time.Hour)},
+		}},
+	}
+
+	result := model.CheckResult{ServiceID: 1, ServiceName: "api", CheckedAt: start.Add(30 * time.Minute)}
+	if err := m.Record(context.Background(), model.Service{}, result); err != nil {
+		t.Fatal(err)
+	}
+	if rules.rules[0].Firing || len(notifier.sent) != 0 {
+		t.Fatalf("rule started firing during maintenance, sent %+v", notifier.sent)
+	}
+
+	result.CheckedAt = start.Add(90 * time.Minute)
+	if err := m.Record(context.Background(), model.Service{}, result); err != nil {
+		t.Fatal(err)
+	}
+	if !rules.rules[0].Firing || len(notifier.sent) != 1 || notifier.sent[0].Status != StatusFiring {
+		t.Fatalf("rule should fire once the window ends, sent %+v", notifier.sent)
+	}
+}
+
+func TestManagerTransitionsAroundMaintenance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	notifier := &recordingNotifier{}
+	m := &Manager{
+		Notifiers: []Notifier{notifier},
+		IMaintenanceRepo: &fakeMaintenance{windows: []model.MaintenanceWindow{
+			{Title: "upgrade", StartsAt: start, EndsAt: start.Add(time.Hour)},
+		}},
+	}
+	record := func(minutes int, up bool) {
+		t.Helper()
+		result := model.CheckResult{
+			ServiceID:   1,
+			ServiceName: "api",
+			Success:     up,
+			CheckedAt:   start.Add(time.Duration(minutes) * time.Minute),
+		}
+		if err := m.Record(context.Background(), model.Service{}, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// down inside the window, nothing is sent and nothing to resolve later
+	record(10, false)
+	record(20, true)
+	if len(notifier.sent) != 0 {
+		t.Fatalf("sent %+v during maintenance", notifier.sent)
+	}
+
+	// an outage that outlasts the window pages when it ends
+	record(50, false)
+	record(70, false)
+	if len(notifier.sent) != 1 || notifier.sent[0].Status != StatusFiring {
+		t.Fatalf("sent %+v, want one firing after the window", notifier.sent)
+	}
+	record(80, true)
+	if len(notifier.sent) != 2 || notifier.sent[1].Status != StatusResolved {
+		t.Fatalf("sent %+v, want a resolve", notifier.sent)
+	}
+}
@@ -0,0 +1,191 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+const DefaultNotifyTimeout = 10 * time.Second
+
+type Status string
+
+const (
+	StatusFiring   Status = "firing"
+	StatusResolved Status = "resolved"
+)
+
+type Notification struct {
+	ServiceID   int       `json:"service_id,omitempty"`
+	ServiceName string    `json:"service_name"`
+	Status      Status    `json:"status"`
+	Rule        string    `json:"rule,omitempty"`
+	Message     string    `json:"message"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// Summary is a one line description of the notification.
+func (n Notification) Summary() string {
+	return fmt.Sprintf("[%s] %s: %s", strings.ToUpper(string(n.Status)), n.ServiceName, n.Message)
+}
+
+// Notifier delivers a notification to one destination.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// WebhookNotifier posts the notification as JSON to URL.
+type WebhookNotifier struct {
+	URL    string
+	Header map[string]string
+	Client *http.Client
+}
+
+func (wn *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	return postJSON(ctx, wn.Client, wn.URL, wn.Header, n)
+}
+
+// SlackNotifier posts to a Slack or Mattermost incoming webhook.
+type SlackNotifier struct {
+	URL     string
+	Channel string
+	Client  *http.Client
+}
+
+func (sn *SlackNotifier) Notify(ctx context.Context, n Notification) error {
+	type message struct {
+		Text    string `json:"text"`
+		Channel string `json:"channel,omitempty"`
+	}
+	return postJSON(ctx, sn.Client, sn.URL, nil, message{Text: n.Summary(), Channel: sn.Channel})
+}
+
+// SMTPNotifier mails the notification. Auth is only used when Username is
+// set, STARTTLS whenever the server offers it.
+type SMTPNotifier struct {
+	Addr     string
+	From     string
+	To       []string
+	Username string
+	Password string
+}
+
+func (sn *SMTPNotifier) Notify(ctx context.Context, n Notification) error {
+	host, _, err := net.SplitHostPort(sn.Addr)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", sn.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DefaultNotifyTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if sn.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", sn.Username, sn.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(sn.From); err != nil {
+		return err
+	}
+	for _, to := range sn.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(sn.message(n)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (sn *SMTPNotifier) message(n Notification) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", sn.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(sn.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", headerValue(n.Summary()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.OccurredAt.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&msg, "Service: %s\r\nStatus: %s\r\n", n.ServiceName, n.Status)
+	if n.Rule != "" {
+		fmt.Fprintf(&msg, "Rule: %s\r\n", n.Rule)
+	}
+	fmt.Fprintf(&msg, "Time: %s\r\n\r\n%s\r\n", n.OccurredAt.Format(time.RFC3339), n.Message)
+	return msg.Bytes()
+}
+
+// headerValue makes s safe to use as a header value, line breaks would let
+// service names inject headers.
+func headerValue(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '\r' || r == '\n' {
+			return ' '
+		}
+		return r
+	}, s)
+	return mime.QEncoding.Encode("utf-8", s)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, header map[string]string, v interface{}) error {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultNotifyTimeout}
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package alert
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNotification = Notification{
+	ServiceID:   1,
+	ServiceName: "api",
+	Status:      StatusFiring,
+	Rule:        "down",
+	Message:     "connection refused",
+	OccurredAt:  time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC),
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var (
+		got    Notification
+		header string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Token")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	wn := &WebhookNotifier{URL: srv.URL, Header: map[string]string{"X-Token": "t0k"}}
+	if err := wn.Notify(context.Background(), testNotification); err != nil {
+		t.Fatal(err)
+	}
+	if got != testNotification {
+		t.Errorf("got %+v, want %+v", got, testNotification)
+	}
+	if header != "t0k" {
+		t.Errorf("X-Token = %q", header)
+	}
+}
+
+func TestWebhookNotifierStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	err := (&WebhookNotifier{URL: srv.URL}).Notify(context.Background(), testNotification)
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("err = %v, want the status", err)
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var got struct {
+		Text    string `json:"text"`
+		Channel string `json:"channel"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	sn := &SlackNotifier{URL: srv.URL, Channel: "#ops"}
+	if err := sn.Notify(context.Background(), testNotification); err != nil {
+		t.Fatal(err)
+	}
+	if got.Text != "[FIRING] api: connection refused" || got.Channel != "#ops" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+// smtpServer accepts one mail and sends its data on the returned channel.
+func smtpServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	data := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ready")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var msg strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					msg.WriteString(line)
+				}
+				data <- msg.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), data
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, data := smtpServer(t)
+
+	n := testNotification
+	n.ServiceName = "api\r\nBcc: victim@example.com"
+	sn := &SMTPNotifier{Addr: addr, From: "monitor@example.com", To: []string{"ops@example.com"}}
+	if err := sn.Notify(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-data
+	headers, _, _ := strings.Cut(msg, "\r\n\r\n")
+	for _, header := range strings.Split(headers, "\r\n") {
+		if strings.HasPrefix(header, "Bcc:") {
+			t.Fatalf("service name injected a header:\n%s", headers)
+		}
+	}
+	if !strings.Contains(headers, "Subject: [FIRING] api  Bcc: victim@example.com: connection refused") {
+		t.Errorf("unexpected headers:\n%s", headers)
+	}
+}
+
+func TestSMTPNotifierTimeout(t *testing.T) {
+	// the server accepts but never greets
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(2 * time.Second)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	sn := &SMTPNotifier{Addr: ln.Addr().String(), From: "monitor@example.com", To: []string{"ops@example.com"}}
+	if err := sn.Notify(ctx, testNotification); err == nil {
+		t.Fatal("expected a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Notify took %s, the context deadline was ignored", elapsed)
+	}
+}
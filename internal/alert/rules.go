@@ -0,0 +1,103 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"time"
+)
+
+// DefaultRuleWindow is used by windowed rules without a window.
+const DefaultRuleWindow = 5 * time.Minute
+
+// ValidateRule reports whether rule can be evaluated.
+func ValidateRule(rule model.AlertRule) error {
+	switch rule.Kind {
+	case model.RuleConsecutiveFailures:
+		if rule.Threshold < 1 || rule.Threshold != float64(int(rule.Threshold)) {
+			return fmt.Errorf("threshold of %s must be a positive integer", rule.Kind)
+		}
+	case model.RuleErrorRate:
+		if rule.Threshold < 0 || rule.Threshold >= 100 {
+			return fmt.Errorf("threshold of %s must be a percentage in [0, 100)", rule.Kind)
+		}
+	case model.RuleLatencyP95:
+		if rule.Threshold <= 0 {
+			return fmt.Errorf("threshold of %s must be positive", rule.Kind)
+		}
+	default:
+		return fmt.Errorf("unknown rule kind %q", rule.Kind)
+	}
+	if rule.Window < 0 {
+		return fmt.Errorf("window must not be negative")
+	}
+	return nil
+}
+
+// Describe returns a short human readable form of rule.
+func Describe(rule model.AlertRule) string {
+	window := ruleWindow(rule)
+	switch rule.Kind {
+	case model.RuleConsecutiveFailures:
+		return fmt.Sprintf("%d consecutive failures", int(rule.Threshold))
+	case model.RuleErrorRate:
+		return fmt.Sprintf("error rate above %g%% over %s", rule.Threshold, window)
+	case model.RuleLatencyP95:
+		return fmt.Sprintf("p95 latency above %gms over %s", rule.Threshold, window)
+	}
+	return string(rule.Kind)
+}
+
+// evaluate reports whether the condition of rule holds after result, with
+// a message describing the current value.
+func evaluate(ctx context.Context, checks repository.IServiceChecksRepository, rule model.AlertRule, result model.CheckResult) (bool, string, error) {
+	to := result.CheckedAt.Add(time.Nanosecond)
+	from := to.Add(-ruleWindow(rule))
+
+	switch rule.Kind {
+	case model.RuleConsecutiveFailures:
+		if result.Success {
+			return false, "service is up again", nil
+		}
+		n := int(rule.Threshold)
+		latest, err := checks.Latest(ctx, rule.ServiceID, n)
+		if err != nil {
+			return false, "", err
+		}
+		if len(latest) < n {
+			return false, "", nil
+		}
+		for _, check := range latest {
+			if check.Success {
+				return false, "", nil
+			}
+		}
+		return true, fmt.Sprintf("%d consecutive failures, last: %s", n, result.Error), nil
+
+	case model.RuleErrorRate:
+		total, failed, err := checks.CountFailures(ctx, rule.ServiceID, from, to)
+		if err != nil || total == 0 {
+			// no data, keep the current state
+			return rule.Firing, "", err
+		}
+		rate := float64(failed) / float64(total) * 100
+		return rate > rule.Threshold, fmt.Sprintf("error rate is %.1f%% (%d of %d checks)", rate, failed, total), nil
+
+	case model.RuleLatencyP95:
+		stats, err := checks.LatencyStats(ctx, rule.ServiceID, from, to, 0)
+		if err != nil || stats.Count == 0 {
+			return rule.Firing, "", err
+		}
+		return stats.P95 > rule.Threshold, fmt.Sprintf("p95 latency is %.0fms", stats.P95), nil
+	}
+
+	return false, "", fmt.Errorf("unknown rule kind %q", rule.Kind)
+}
+
+func ruleWindow(rule model.AlertRule) time.Duration {
+	if rule.Window <= 0 {
+		return DefaultRuleWindow
+	}
+	return time.Duration(rule.Window) * time.Second
+}
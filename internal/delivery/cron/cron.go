@@ -0,0 +1,245 @@
+package cron
+
+import (
+	"context"
+	"math/rand"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/probe"
+	"monitoring/internal/repository"
+	"monitoring/internal/util/midlog"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSchedule = "1m"
+	DefaultRefresh  = time.Minute
+)
+
+var logger = midlog.LoggerForModule("cron")
+
+// Cron runs every service on its own schedule. Services are reloaded from
+// the database every Refresh so added, updated and deleted services are
+// picked up without a restart.
+type Cron struct {
+	Engine          *probe.Engine
+	IServicesRepo   repository.IServicesRepository
+	DefaultSchedule Schedule
+	Jitter          time.Duration
+	Refresh         time.Duration
+
+	mu      sync.Mutex
+	jobs    map[int]*job
+	running map[int]bool
+	wg      sync.WaitGroup
+	cancel  context.CancelFunc
+}
+
+type job struct {
+	spec    string
+	service model.Service
+	cancel  context.CancelFunc
+}
+
+func New() (*Cron, error) {
+	cfg := GlobalConfig.Cron
+
+	spec := cfg.CPUInterval
+	if spec == "" {
+		spec = DefaultSchedule
+	}
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var jitter time.Duration
+	if cfg.Jitter != "" {
+		jitter, err = time.ParseDuration(cfg.Jitter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	refresh := DefaultRefresh
+	if cfg.Refresh != "" {
+		refresh, err = time.ParseDuration(cfg.Refresh)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	engine := probe.NewEngine()
+	return &Cron{
+		Engine:          engine,
+		IServicesRepo:   engine.IServicesRepo,
+		DefaultSchedule: schedule,
+		Jitter:          jitter,
+		Refresh:         refresh,
+		jobs:            map[int]*job{},
+		running:         map[int]bool{},
+	}, nil
+}
+
+// Start loads the services and schedules them in the background.
+func (c *Cron) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.Refresh)
+		defer ticker.Stop()
+		for {
+			if err := c.reload(ctx); err != nil {
+				logger.ErrorE(err, "Error reloading services")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels all schedules and waits for the checks in flight to finish,
+// or for ctx to expire.
+func (c *Cron) Stop(ctx context.Context) error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Cron) reload(ctx context.Context) error {
+	services, err := c.IServicesRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[int]bool, len(services))
+	for _, service := range services {
+		seen[service.ID] = true
+
+		spec := ""
+		if service.Interval != nil {
+			spec = *service.Interval
+		}
+
+		if j, ok := c.jobs[service.ID]; ok {
+			if name(j.service) != name(service) {
+				// the state of the old name would never be updated again
+				c.Engine.Forget(j.service)
+			}
+			if j.spec == spec {
+				j.service = service
+				continue
+			}
+			j.cancel()
+			delete(c.jobs, service.ID)
+		}
+
+		schedule := c.DefaultSchedule
+		if spec != "" {
+			schedule, err = ParseSchedule(spec)
+			if err != nil {
+				logger.ErrorEF(err, "Invalid interval of service %d, using the default", service.ID)
+				schedule = c.DefaultSchedule
+			}
+		}
+
+		jobCtx, cancel := context.WithCancel(ctx)
+		j := &job{spec: spec, service: service, cancel: cancel}
+		c.jobs[service.ID] = j
+		c.wg.Add(1)
+		go c.runJob(jobCtx, j, schedule)
+	}
+
+	for id, j := range c.jobs {
+		if !seen[id] {
+			j.cancel()
+			delete(c.jobs, id)
+			c.Engine.Forget(j.service)
+		}
+	}
+
+	return nil
+}
+
+func name(service model.Service) string {
+	if service.Name == nil {
+		return ""
+	}
+	return *service.Name
+}
+
+func (c *Cron) runJob(ctx context.Context, j *job, schedule Schedule) {
+	defer c.wg.Done()
+	for {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			logger.ErrorF("Schedule %q never fires", j.spec)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next) + c.jitter())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		c.mu.Lock()
+		service := j.service
+		c.mu.Unlock()
+		c.check(service)
+	}
+}
+
+// check runs a single check unless the previous run of the same service is
+// still in flight. Checks aren't bound to the scheduler context so a
+// shutdown lets them finish and record their result.
+func (c *Cron) check(service model.Service) {
+	c.mu.Lock()
+	if c.running[service.ID] {
+		c.mu.Unlock()
+		logger.WarnF("Skipping service %d, previous check is still running", service.ID)
+		return
+	}
+	c.running[service.ID] = true
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.running, service.ID)
+		c.mu.Unlock()
+	}()
+
+	c.Engine.Check(context.Background(), service)
+}
+
+func (c *Cron) jitter() time.Duration {
+	if c.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(c.Jitter)))
+}
@@ -0,0 +1,238 @@
+package cron
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"0s",
+		"-1m",
+		"@every 0s",
+		"@sometimes",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"a * * * *",
+		"5-1 * * * *",
+		"1-a * * * *",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	tehran := mustLocation(t, "Asia/Tehran")
+	kolkata := mustLocation(t, "Asia/Kolkata")
+	newYork := mustLocation(t, "America/New_York")
+	santiago := mustLocation(t, "America/Santiago")
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "duration",
+			spec: "30s",
+			from: time.Date(2024, 1, 1, 10, 0, 10, 0, time.UTC),
+			want: time.Date(2024, 1, 1, 10, 0, 40, 0, time.UTC),
+		},
+		{
+			name: "every",
+			spec: "@every 5m",
+			from: time.Date(2024, 1, 1, 10, 0, 10, 0, time.UTC),
+			want: time.Date(2024, 1, 1, 10, 5, 10, 0, time.UTC),
+		},
+		{
+			name: "hourly",
+			spec: "@hourly",
+			from: time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "daily",
+			spec: "@daily",
+			from: time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// 2024-01-01 is a monday
+			name: "weekly",
+			spec: "@weekly",
+			from: time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly",
+			spec: "@monthly",
+			from: time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC),
+			want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "yearly",
+			spec: "@yearly",
+			from: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "strictly after",
+			spec: "*/5 * * * *",
+			from: time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 1, 10, 10, 0, 0, time.UTC),
+		},
+		{
+			name: "seconds are dropped",
+			spec: "* * * * *",
+			from: time.Date(2024, 1, 1, 10, 5, 30, 0, time.UTC),
+			want: time.Date(2024, 1, 1, 10, 6, 0, 0, time.UTC),
+		},
+		{
+			name: "step with range",
+			spec: "10-30/10 * * * *",
+			from: time.Date(2024, 1, 1, 10, 21, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "step with start",
+			spec: "5/20 * * * *",
+			from: time.Date(2024, 1, 1, 10, 46, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 1, 11, 5, 0, 0, time.UTC),
+		},
+		{
+			name: "list",
+			spec: "0 8,12,18 * * *",
+			from: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			// 2024-01-05 is a friday
+			name: "weekdays range",
+			spec: "0 9-17 * * 1-5",
+			from: time.Date(2024, 1, 5, 18, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as 7",
+			spec: "0 0 * * 7",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// either the 15th or a monday
+			name: "day of month or week",
+			spec: "0 0 15 * 1",
+			from: time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap day",
+			spec: "0 0 29 2 *",
+			from: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "never matches",
+			spec: "0 0 30 2 *",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Time{},
+		},
+		{
+			name: "half hour offset",
+			spec: "0 12 * * *",
+			from: time.Date(2024, 1, 1, 8, 10, 0, 0, tehran),
+			want: time.Date(2024, 1, 1, 12, 0, 0, 0, tehran),
+		},
+		{
+			name: "half hour offset with step",
+			spec: "*/15 9 * * *",
+			from: time.Date(2024, 1, 1, 8, 50, 0, 0, kolkata),
+			want: time.Date(2024, 1, 1, 9, 0, 0, 0, kolkata),
+		},
+		{
+			name: "half hour offset within the hour",
+			spec: "*/15 9 * * *",
+			from: time.Date(2024, 1, 1, 9, 0, 0, 0, kolkata),
+			want: time.Date(2024, 1, 1, 9, 15, 0, 0, kolkata),
+		},
+		{
+			// 02:00 doesn't exist on 2024-03-10, the clock jumps to 03:00
+			name: "dst starts",
+			spec: "0 * * * *",
+			from: time.Date(2024, 3, 10, 1, 30, 0, 0, newYork),
+			want: time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "dst starts in the missing hour",
+			spec: "30 2 * * *",
+			from: time.Date(2024, 3, 10, 0, 0, 0, 0, newYork),
+			want: time.Date(2024, 3, 11, 2, 30, 0, 0, newYork),
+		},
+		{
+			name: "dst starts daily",
+			spec: "0 4 * * *",
+			from: time.Date(2024, 3, 10, 0, 0, 0, 0, newYork),
+			want: time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			// 01:00 to 02:00 happens twice on 2024-11-03, hourly runs on both
+			name: "dst ends",
+			spec: "0 * * * *",
+			from: time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC).In(newYork),
+			want: time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "dst ends daily",
+			spec: "0 4 * * *",
+			from: time.Date(2024, 11, 3, 0, 0, 0, 0, newYork),
+			want: time.Date(2024, 11, 3, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			// midnight doesn't exist on 2024-09-08, times that don't exist
+			// are skipped
+			name: "dst starts at midnight",
+			spec: "@daily",
+			from: time.Date(2024, 9, 7, 12, 0, 0, 0, santiago),
+			want: time.Date(2024, 9, 9, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "dst starts at midnight hourly",
+			spec: "0 * * * *",
+			from: time.Date(2024, 9, 7, 23, 30, 0, 0, santiago),
+			want: time.Date(2024, 9, 8, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q): %v", tt.spec, err)
+			}
+			got := schedule.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want.In(tt.from.Location()))
+			}
+		})
+	}
+}
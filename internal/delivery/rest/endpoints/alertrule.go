@@ -0,0 +1,122 @@
+package endpoints
+
+import (
+	"errors"
+	"monitoring/internal/alert"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AlertRulesEndpoints struct {
+	IAlertRulesUC usecase.IAlertRulesUsecase
+}
+
+func NewAlertRulesEndpoints() *AlertRulesEndpoints {
+	return &AlertRulesEndpoints{
+		IAlertRulesUC: &usecase.AlertRulesUsecase{
+			IAlertRulesRepo: &repository.AlertRulesRepository{DB: GlobalPG},
+			IServicesRepo:   &repository.ServicesRepository{DB: GlobalPG},
+			IRuleResolver:   alert.NewManager(),
+		},
+	}
+}
+
+type alertRuleRequest struct {
+	ID          int                 `json:"id"`
+	ServiceName string              `json:"service_name"`
+	Kind        model.AlertRuleKind `json:"kind"`
+	Threshold   float64             `json:"threshold"`
+	Window      int                 `json:"window_seconds"`
+	Enabled     *bool               `json:"enabled"`
+}
+
+func (r alertRuleRequest) rule() model.AlertRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	return model.AlertRule{
+		ID:          r.ID,
+		ServiceName: r.ServiceName,
+		Kind:        r.Kind,
+		Threshold:   r.Threshold,
+		Window:      r.Window,
+		Enabled:     enabled,
+	}
+}
+
+// List returns the alert rules, optionally of the service query parameter.
+func (ae *AlertRulesEndpoints) List(c echo.Context) error {
+	rules, err := ae.IAlertRulesUC.List(c.Request().Context(), c.QueryParam("service"))
+	if errors.Is(err, usecase.ErrServiceNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"rules": rules,
+	})
+}
+
+func (ae *AlertRulesEndpoints) Add(c echo.Context) error {
+	var req alertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+	if req.ServiceName == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "service_name must be filled"})
+	}
+
+	err := ae.IAlertRulesUC.Add(c.Request().Context(), req.rule())
+	if errors.Is(err, usecase.ErrServiceNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Alert rule added",
+	})
+}
+
+func (ae *AlertRulesEndpoints) Update(c echo.Context) error {
+	var req alertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+	if req.ID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "id must be filled"})
+	}
+
+	err := ae.IAlertRulesUC.Update(c.Request().Context(), req.rule())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Alert rule updated",
+	})
+}
+
+func (ae *AlertRulesEndpoints) Delete(c echo.Context) error {
+	var req alertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+	if req.ID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "id must be filled"})
+	}
+
+	err := ae.IAlertRulesUC.Delete(c.Request().Context(), req.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Alert rule deleted",
+	})
+}
@@ -0,0 +1,65 @@
+package endpoints
+
+import (
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/usecase"
+	"monitoring/internal/util"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuditEndpoints struct {
+	IAuditUC usecase.IAuditUsecase
+}
+
+func NewAuditEndpoints() *AuditEndpoints {
+	return &AuditEndpoints{
+		IAuditUC: NewAuditUC(),
+	}
+}
+
+// NewAuditUC returns the audit usecase the usecases making administrative
+// changes record them with.
+func NewAuditUC() *usecase.AuditUsecase {
+	return &usecase.AuditUsecase{
+		IAuditRepo: &repository.AuditRepository{DB: GlobalPG},
+	}
+}
+
+// List returns audit events, newest first, filtered by the actor, action,
+// target_type, target, from, to, limit and offset query parameters. Times
+// are RFC 3339.
+func (ae *AuditEndpoints) List(c echo.Context) error {
+	params := util.NewUrlParams(c.QueryParams())
+
+	filter := model.AuditFilter{
+		Actor:      params.Get("actor"),
+		Action:     model.AuditAction(params.Get("action")),
+		TargetType: params.Get("target_type"),
+		Target:     params.Get("target"),
+	}
+	var err error
+	if filter.From, err = parseTimeParam(params.Get("from")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if filter.To, err = parseTimeParam(params.Get("to")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if filter.Limit, err = parseIntParam(params.Get("limit")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if filter.Offset, err = parseIntParam(params.Get("offset")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+
+	events, err := ae.IAuditUC.List(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"audit_events": events,
+	})
+}
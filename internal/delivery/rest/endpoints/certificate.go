@@ -0,0 +1,34 @@
+package endpoints
+
+import (
+	. "monitoring/internal/globals"
+	"monitoring/internal/repository"
+	"monitoring/internal/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CertificatesEndpoints struct {
+	ICertificatesUC usecase.ICertificatesUsecase
+}
+
+func NewCertificatesEndpoints() *CertificatesEndpoints {
+	return &CertificatesEndpoints{
+		ICertificatesUC: &usecase.CertificatesUsecase{
+			ICertificatesRepo: &repository.CertificatesRepository{DB: GlobalPG},
+		},
+	}
+}
+
+// List returns the certificates of every service, soonest expiry first.
+func (ce *CertificatesEndpoints) List(c echo.Context) error {
+	certs, err := ce.ICertificatesUC.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"certificates": certs,
+	})
+}
@@ -0,0 +1,82 @@
+package endpoints
+
+import (
+	"errors"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/usecase"
+	"monitoring/internal/util"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ErrorReportsEndpoints struct {
+	IErrorReportsUC usecase.IErrorReportsUsecase
+}
+
+func NewErrorReportsEndpoints() *ErrorReportsEndpoints {
+	return &ErrorReportsEndpoints{
+		IErrorReportsUC: &usecase.ErrorReportsUsecase{
+			IErrorReportsRepo: &repository.ErrorReportsRepository{DB: GlobalPG},
+		},
+	}
+}
+
+// List returns error reports filtered by the service, from, to, limit and
+// offset query parameters. Times are RFC 3339.
+func (ee *ErrorReportsEndpoints) List(c echo.Context) error {
+	params := util.NewUrlParams(c.QueryParams())
+
+	var (
+		filter model.ErrorReportFilter
+		err    error
+	)
+	filter.ServiceName = params.Get("service")
+	if filter.From, err = parseTimeParam(params.Get("from")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if filter.To, err = parseTimeParam(params.Get("to")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if filter.Limit, err = parseIntParam(params.Get("limit")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if filter.Offset, err = parseIntParam(params.Get("offset")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+
+	reports, err := ee.IErrorReportsUC.List(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"error_reports": reports,
+	})
+}
+
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("times must be in RFC 3339 format")
+	}
+	return t, nil
+}
+
+func parseIntParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New("invalid number: " + value)
+	}
+	return i, nil
+}
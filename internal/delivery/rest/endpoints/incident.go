@@ -0,0 +1,117 @@
+package endpoints
+
+import (
+	"errors"
+	"monitoring/internal/delivery/rest/middlewares"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/usecase"
+	"monitoring/internal/util"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IncidentsEndpoints struct {
+	IIncidentsUC usecase.IIncidentsUsecase
+}
+
+func NewIncidentsEndpoints() *IncidentsEndpoints {
+	return &IncidentsEndpoints{
+		IIncidentsUC: &usecase.IncidentsUsecase{
+			IIncidentsRepo:   &repository.IncidentsRepository{DB: GlobalPG},
+			IUserServiceRepo: &repository.UserServiceRepository{DB: GlobalPG},
+		},
+	}
+}
+
+type incidentRequest struct {
+	ID      int64  `json:"id"`
+	Message string `json:"message"`
+}
+
+// List returns incidents filtered by the service, status, limit and offset
+// query parameters.
+func (ie *IncidentsEndpoints) List(c echo.Context) error {
+	principal := middlewares.GetPrincipal(c)
+
+	params := util.NewUrlParams(c.QueryParams())
+	filter := model.IncidentFilter{
+		ServiceName: params.Get("service"),
+		Status:      model.IncidentStatus(params.Get("status")),
+	}
+	var err error
+	if filter.Limit, err = parseIntParam(params.Get("limit")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if filter.Offset, err = parseIntParam(params.Get("offset")); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+
+	incidents, err := ie.IIncidentsUC.List(c.Request().Context(), filter, int(principal.Role), principal.UserID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"incidents": incidents,
+	})
+}
+
+// Timeline returns the incident given by the id query parameter with every
+// state change, check failure and comment in order.
+func (ie *IncidentsEndpoints) Timeline(c echo.Context) error {
+	id, err := strconv.ParseInt(c.QueryParam("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "id must be a number"})
+	}
+	principal := middlewares.GetPrincipal(c)
+
+	timeline, err := ie.IIncidentsUC.Timeline(c.Request().Context(), id, int(principal.Role), principal.UserID)
+	if errors.Is(err, usecase.ErrIncidentNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, timeline)
+}
+
+func (ie *IncidentsEndpoints) Acknowledge(c echo.Context) error {
+	var req incidentRequest
+	if err := c.Bind(&req); err != nil || req.ID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+	principal := middlewares.GetPrincipal(c)
+
+	err := ie.IIncidentsUC.Acknowledge(c.Request().Context(), req.ID, req.Message, principal.Name, int(principal.Role), principal.UserID)
+	if errors.Is(err, usecase.ErrIncidentNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Incident acknowledged",
+	})
+}
+
+func (ie *IncidentsEndpoints) Comment(c echo.Context) error {
+	var req incidentRequest
+	if err := c.Bind(&req); err != nil || req.ID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+	principal := middlewares.GetPrincipal(c)
+
+	err := ie.IIncidentsUC.Comment(c.Request().Context(), req.ID, req.Message, principal.Name, int(principal.Role), principal.UserID)
+	if errors.Is(err, usecase.ErrIncidentNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Comment added",
+	})
+}
@@ -0,0 +1,70 @@
+package endpoints
+
+import (
+	"errors"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MaintenanceEndpoints struct {
+	IMaintenanceUC usecase.IMaintenanceUsecase
+}
+
+func NewMaintenanceEndpoints() *MaintenanceEndpoints {
+	return &MaintenanceEndpoints{
+		IMaintenanceUC: &usecase.MaintenanceUsecase{
+			IMaintenanceRepo: &repository.MaintenanceRepository{DB: GlobalPG},
+			IServicesRepo:    &repository.ServicesRepository{DB: GlobalPG},
+		},
+	}
+}
+
+func (me *MaintenanceEndpoints) List(c echo.Context) error {
+	windows, err := me.IMaintenanceUC.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"maintenance_windows": windows,
+	})
+}
+
+// Add creates a maintenance window, leaving service_name empty applies it
+// to every service.
+func (me *MaintenanceEndpoints) Add(c echo.Context) error {
+	var window model.MaintenanceWindow
+	if err := c.Bind(&window); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+
+	err := me.IMaintenanceUC.Add(c.Request().Context(), window)
+	if errors.Is(err, usecase.ErrServiceNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Maintenance window added",
+	})
+}
+
+func (me *MaintenanceEndpoints) Delete(c echo.Context) error {
+	var window model.MaintenanceWindow
+	if err := c.Bind(&window); err != nil || window.ID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+
+	err := me.IMaintenanceUC.Delete(c.Request().Context(), window.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Maintenance window deleted",
+	})
+}
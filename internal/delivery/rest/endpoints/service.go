@@ -3,32 +3,52 @@ package endpoints
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"log"
+	"monitoring/internal/delivery/cron"
+	"monitoring/internal/delivery/rest/middlewares"
 	. "monitoring/internal/globals"
 	"monitoring/internal/model"
+	"monitoring/internal/probe"
 	"monitoring/internal/repository"
 	"monitoring/internal/repository/userrepo"
 	"monitoring/internal/usecase"
 	"monitoring/internal/usecase/useruc"
+	"monitoring/internal/util"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
+// DefaultStatsWindow is used by the uptime and statistics endpoints when
+// no from parameter is given.
+const DefaultStatsWindow = 30 * 24 * time.Hour
+
 type ServicesEndpoints struct {
 	IServicesUC usecase.IServicesUsecase
+	IUptimeUC   usecase.IUptimeUsecase
+	IStatsUC    usecase.IStatisticsUsecase
 	IUseruc     useruc.IUserUsecase
 }
 
 func NewServicesEndpoints() *ServicesEndpoints {
 
+	servicesRepo := &repository.ServicesRepository{DB: GlobalPG}
+	checksRepo := &repository.ServiceChecksRepository{DB: GlobalPG}
 	return &ServicesEndpoints{
 		IServicesUC: &usecase.ServicesUsecase{
-			IServicesRepo: &repository.ServicesRepository{DB: GlobalPG},
+			IServicesRepo: servicesRepo,
+			IAuditUC:      NewAuditUC(),
+		},
+		IUptimeUC: &usecase.UptimeUsecase{
+			IServicesRepo:      servicesRepo,
+			IServiceChecksRepo: checksRepo,
+			IMaintenanceRepo:   &repository.MaintenanceRepository{DB: GlobalPG},
+		},
+		IStatsUC: &usecase.StatisticsUsecase{
+			IServicesRepo:      servicesRepo,
+			IServiceChecksRepo: checksRepo,
 		},
 		IUseruc: &useruc.UserUsecase{
 			IUserRepo: &userrepo.UserRepo{DB: GlobalPG},
@@ -39,12 +59,6 @@ func NewServicesEndpoints() *ServicesEndpoints {
 
 func (se *ServicesEndpoints) ListServices(c echo.Context) error {
 
-	user := c.Get("user").(*jwt.Token)
-	claims := user.Claims.(*model.JwtCustomClaims)
-	if claims.RoleId != 1 {
-		return c.JSON(http.StatusForbidden, "Forbidden")
-	}
-
 	services, err := se.IServicesUC.List(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
@@ -55,12 +69,6 @@ func (se *ServicesEndpoints) ListServices(c echo.Context) error {
 
 func (se *ServicesEndpoints) AddService(c echo.Context) error {
 
-	user := c.Get("user").(*jwt.Token)
-	claims := user.Claims.(*model.JwtCustomClaims)
-	if claims.RoleId != 1 {
-		return c.JSON(http.StatusForbidden, "Forbidden")
-	}
-
 	service, userIds, err := se.checkPostParams(c)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, err)
@@ -81,12 +89,6 @@ func (se *ServicesEndpoints) AddService(c echo.Context) error {
 
 func (se *ServicesEndpoints) UpdateService(c echo.Context) error {
 
-	user := c.Get("user").(*jwt.Token)
-	claims := user.Claims.(*model.JwtCustomClaims)
-	if claims.RoleId != 1 {
-		return c.JSON(http.StatusForbidden, "Forbidden")
-	}
-
 	service, _, err := se.checkPostParams(c)
 	if err != nil {
 		return err
@@ -103,12 +105,6 @@ func (se *ServicesEndpoints) UpdateService(c echo.Context) error {
 
 func (se *ServicesEndpoints) DeleteService(c echo.Context) error {
 
-	user := c.Get("user").(*jwt.Token)
-	claims := user.Claims.(*model.JwtCustomClaims)
-	if claims.RoleId != 1 {
-		return c.JSON(http.StatusForbidden, "Forbidden")
-	}
-
 	type RequestBody struct {
 		Name string `json:"name"`
 	}
@@ -139,19 +135,8 @@ func (se *ServicesEndpoints) DeleteService(c echo.Context) error {
 
 func (se *ServicesEndpoints) GetUserService(c echo.Context) error {
 
-	var err error
-	tokenString := c.Get("user").(*jwt.Token)
-	token, err := jwt.Parse(tokenString.Raw, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte("secret"), nil
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
+	principal := middlewares.GetPrincipal(c)
 
-	jwtToken := token.Claims.(jwt.MapClaims)
 	type RequestBody struct {
 		Name string `json:"name"`
 	}
@@ -170,12 +155,21 @@ func (se *ServicesEndpoints) GetUserService(c echo.Context) error {
 		service.Name = &requestBody.Name
 	}
 
-	userId, err := se.getUsrId(c, jwtToken["name"].(string))
+	svc, err := se.IServicesUC.GetUserService(c.Request().Context(), requestBody.Name, int(principal.Role), principal.UserID)
+
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 
-	svc, err := se.IServicesUC.GetUserService(c.Request().Context(), requestBody.Name, int(jwtToken["role"].(float64)), userId)
+	return c.JSON(http.StatusOK, svc)
+
+}
+
+func (se *ServicesEndpoints) GetUserServices(c echo.Context) error {
+
+	principal := middlewares.GetPrincipal(c)
+
+	svc, err := se.IServicesUC.GetUserServices(c.Request().Context(), int(principal.Role), principal.UserID)
 
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
@@ -185,34 +179,80 @@ func (se *ServicesEndpoints) GetUserService(c echo.Context) error {
 
 }
 
-func (se *ServicesEndpoints) GetUserServices(c echo.Context) error {
+// GetServiceUptime returns the availability of the service given by the
+// name query parameter between from and to (RFC 3339, defaults to the last
+// 30 days).
+func (se *ServicesEndpoints) GetServiceUptime(c echo.Context) error {
 
-	var err error
-	tokenString := c.Get("user").(*jwt.Token)
-	token, err := jwt.Parse(tokenString.Raw, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte("secret"), nil
-	})
+	principal := middlewares.GetPrincipal(c)
+
+	name, from, to, err := se.checkWindowParams(c)
 	if err != nil {
-		log.Fatal(err)
+		return c.JSON(http.StatusBadRequest, err.Error())
 	}
 
-	jwtToken := token.Claims.(jwt.MapClaims)
-	userId, err := se.getUsrId(c, jwtToken["name"].(string))
+	uptime, err := se.IUptimeUC.GetUptime(c.Request().Context(), name, int(principal.Role), principal.UserID, from, to)
+	if errors.Is(err, usecase.ErrServiceNotFound) {
+		return c.JSON(http.StatusNotFound, err.Error())
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 
-	svc, err := se.IServicesUC.GetUserServices(c.Request().Context(), int(jwtToken["role"].(float64)), userId)
+	return c.JSON(http.StatusOK, uptime)
+}
+
+// GetServiceStats returns latency percentiles and a histogram with the
+// given number of buckets for the service given by the name query parameter.
+func (se *ServicesEndpoints) GetServiceStats(c echo.Context) error {
 
+	principal := middlewares.GetPrincipal(c)
+
+	name, from, to, err := se.checkWindowParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	buckets, err := parseIntParam(c.QueryParam("buckets"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := se.IStatsUC.GetLatencyStats(c.Request().Context(), name, int(principal.Role), principal.UserID, from, to, buckets)
+	if errors.Is(err, usecase.ErrServiceNotFound) {
+		return c.JSON(http.StatusNotFound, err.Error())
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 
-	return c.JSON(http.StatusOK, svc)
+	return c.JSON(http.StatusOK, stats)
+}
 
+func (se *ServicesEndpoints) checkWindowParams(c echo.Context) (name string, from, to time.Time, err error) {
+	params := util.NewUrlParams(c.QueryParams())
+
+	name = params.Get("name")
+	if name == "" {
+		return "", from, to, errors.New("name must be filled")
+	}
+	if from, err = parseTimeParam(params.Get("from")); err != nil {
+		return "", from, to, err
+	}
+	if to, err = parseTimeParam(params.Get("to")); err != nil {
+		return "", from, to, err
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-DefaultStatsWindow)
+	}
+	if !from.Before(to) {
+		return "", from, to, errors.New("from must be before to")
+	}
+
+	return name, from, to, nil
 }
 
 func (se *ServicesEndpoints) checkGetParams(c echo.Context) (service model.Service, err error) {
@@ -276,10 +316,15 @@ func (se *ServicesEndpoints) checkPostParams(c echo.Context) (service model.Serv
 		Name          string `json:"name,omitempty"`
 		Address       string `json:"address,omitempty"`
 		Method        string `json:"method,omitempty"`
+		Type          string `json:"type,omitempty"`
+		Config        string `json:"config,omitempty"`
 		Header        string `json:"header,omitempty"`
 		Body          string `json:"body,omitempty"`
 		AccessLevel   string `json:"accesslevel,omitempty"`
 		ExecutionTime string `json:"execution_time,omitempty"`
+		Interval      string `json:"interval,omitempty"`
+		Assertions    string `json:"assertions,omitempty"`
+		Public        string `json:"public,omitempty"`
 		AllowedUsers  string `json:"users,omitempty"`
 	}
 
@@ -292,7 +337,7 @@ func (se *ServicesEndpoints) checkPostParams(c echo.Context) (service model.Serv
 	if req.Name == "" || req.AccessLevel == "" {
 		return model.Service{}, userIds, errors.New("service name & accesslevel must be filled")
 	}
-	if req.Address == "" && req.Method == "" && req.Header == "" && req.Body == "" && req.ExecutionTime == "" {
+	if req.Address == "" && req.Method == "" && req.Header == "" && req.Body == "" && req.ExecutionTime == "" && req.Interval == "" && req.Assertions == "" && req.Type == "" && req.Config == "" && req.Public == "" {
 		return model.Service{}, userIds, c.JSON(http.StatusBadRequest, "Bad request")
 	}
 
@@ -334,6 +379,45 @@ func (se *ServicesEndpoints) checkPostParams(c echo.Context) (service model.Serv
 		}
 	}
 
+	if req.Interval != "" {
+		if _, err := cron.ParseSchedule(req.Interval); err != nil {
+			return model.Service{}, userIds, err
+		}
+	}
+
+	var checkConfig *model.CheckConfig
+	if req.Config != "" {
+		err = json.Unmarshal([]byte(req.Config), &checkConfig)
+		if err != nil {
+			return model.Service{}, userIds, errors.New("config must be a JSON object")
+		}
+	}
+	err = probe.ValidateCheck(req.Type, checkConfig)
+	if err != nil {
+		return model.Service{}, userIds, err
+	}
+
+	var assertions []model.Assertion
+	if req.Assertions != "" {
+		err = json.Unmarshal([]byte(req.Assertions), &assertions)
+		if err != nil {
+			return model.Service{}, userIds, errors.New("assertions must be a JSON array")
+		}
+		err = probe.ValidateAssertions(assertions)
+		if err != nil {
+			return model.Service{}, userIds, err
+		}
+	}
+
+	var public *bool
+	if req.Public != "" {
+		p, err := strconv.ParseBool(req.Public)
+		if err != nil {
+			return model.Service{}, userIds, errors.New("public must be true or false")
+		}
+		public = &p
+	}
+
 	req.AllowedUsers = strings.Replace(req.AllowedUsers, " ", "", -1)
 	allowU := strings.Split(req.AllowedUsers, ",")
 
@@ -346,14 +430,25 @@ func (se *ServicesEndpoints) checkPostParams(c echo.Context) (service model.Serv
 		userIds = append(userIds, userId)
 	}
 
+	// services without an interval run on the default schedule
+	var interval *string
+	if req.Interval != "" {
+		interval = &req.Interval
+	}
+
 	service = model.Service{
 		Name:          &req.Name,
 		Address:       &req.Address,
 		Method:        &req.Method,
+		Type:          &req.Type,
+		Config:        checkConfig,
 		Header:        headerMap,
 		Body:          bodyMap,
 		AccessLevel:   accLevel,
 		ExecutionTime: &exeTimeInt64,
+		Interval:      interval,
+		Assertions:    assertions,
+		Public:        public,
 	}
 
 	return service, userIds, nil
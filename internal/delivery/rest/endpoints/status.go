@@ -0,0 +1,108 @@
+package endpoints
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type StatusPageEndpoints struct {
+	IStatusPageUC usecase.IStatusPageUsecase
+}
+
+func NewStatusPageEndpoints() *StatusPageEndpoints {
+	return &StatusPageEndpoints{
+		IStatusPageUC: &usecase.StatusPageUsecase{
+			Title:              GlobalConfig.Status.Title,
+			IServicesRepo:      &repository.ServicesRepository{DB: GlobalPG},
+			IServiceChecksRepo: &repository.ServiceChecksRepository{DB: GlobalPG},
+			IIncidentsRepo:     &repository.IncidentsRepository{DB: GlobalPG},
+			IMaintenanceRepo:   &repository.MaintenanceRepository{DB: GlobalPG},
+		},
+	}
+}
+
+// GetJSON returns the public status page as JSON.
+func (se *StatusPageEndpoints) GetJSON(c echo.Context) error {
+	page, err := se.IStatusPageUC.Get(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "status unavailable"})
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+// GetHTML renders the public status page.
+func (se *StatusPageEndpoints) GetHTML(c echo.Context) error {
+	page, err := se.IStatusPageUC.Get(c.Request().Context())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "status unavailable")
+	}
+
+	var buf bytes.Buffer
+	if err := statusTemplate.Execute(&buf, page); err != nil {
+		return c.String(http.StatusInternalServerError, "status unavailable")
+	}
+
+	return c.HTMLBlob(http.StatusOK, buf.Bytes())
+}
+
+var statusTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"percent": func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+	"bar": func(day model.DailyUptime) string {
+		switch {
+		case day.Checks == 0:
+			return "none"
+		case day.Availability >= 99.9:
+			return "up"
+		case day.Availability >= 95:
+			return "degraded"
+		default:
+			return "down"
+		}
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 960px; margin: 2em auto; color: #222; }
+.service { margin: 1.5em 0; }
+.state { float: right; font-weight: bold; }
+.state.up { color: #2e7d32; }
+.state.down { color: #c62828; }
+.bars { display: flex; gap: 1px; height: 32px; margin-top: .4em; }
+.bars span { flex: 1; border-radius: 1px; }
+.bars .up { background: #43a047; }
+.bars .degraded { background: #fb8c00; }
+.bars .down { background: #e53935; }
+.bars .none { background: #ddd; }
+.incident { border-left: 4px solid #e53935; padding: .3em .8em; margin: .5em 0; }
+footer { color: #888; font-size: .8em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Incidents}}<h2>Active incidents</h2>
+{{range .Incidents}}<div class="incident"><strong>{{.ServiceName}}</strong>: {{.Title}} ({{.Status}} since {{.OpenedAt.Format "2006-01-02 15:04 MST"}})</div>
+{{end}}{{end}}
+{{range .Services}}<div class="service">
+<span class="state {{if .Up}}up{{else}}down{{end}}">{{if .Up}}Operational{{else}}Down{{end}}</span>
+<strong>{{.Name}}</strong> <small>{{percent .Uptime}} uptime</small>
+<div class="bars">{{range .Days}}<span class="{{bar .}}" title="{{.Date.Format "2006-01-02"}}{{if .Checks}}: {{percent .Availability}}{{end}}"></span>{{end}}</div>
+</div>
+{{else}}<p>No services are listed.</p>
+{{end}}
+<footer>Updated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}</footer>
+</body>
+</html>
+`))
@@ -0,0 +1,138 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"monitoring/internal/delivery/rest/middlewares"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/stream"
+	"monitoring/internal/usecase"
+	"monitoring/internal/usecase/useruc"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// StreamHeartbeat is how often an idle stream sends a comment line to keep
+// proxies from closing it.
+const StreamHeartbeat = 15 * time.Second
+
+// StreamRecheck is how often a stream checks that its credentials are still
+// valid and re-resolves the services its caller may access.
+const StreamRecheck = time.Minute
+
+type StreamEndpoints struct {
+	IStreamUC usecase.IStreamUsecase
+	ITokenUC  useruc.ITokenUsecase
+	IAPIKeyUC useruc.IAPIKeyUsecase
+}
+
+func NewStreamEndpoints() *StreamEndpoints {
+	users := &userrepo.UserRepo{DB: GlobalPG}
+	return &StreamEndpoints{
+		IStreamUC: &usecase.StreamUsecase{
+			Hub:              stream.Default,
+			IUserServiceRepo: &repository.UserServiceRepository{DB: GlobalPG},
+			IUserRepo:        users,
+		},
+		ITokenUC:  &useruc.TokenUC{ITokenRepo: &userrepo.TokenRepo{DB: GlobalPG}, IUserRepo: users},
+		IAPIKeyUC: &useruc.APIKeyUC{IAPIKeyRepo: &userrepo.APIKeyRepo{DB: GlobalPG}, IUserRepo: users},
+	}
+}
+
+// Stream pushes check results and state changes of the caller's services as
+// server-sent events until the client disconnects, or its token or API key
+// is revoked or expires.
+func (se *StreamEndpoints) Stream(c echo.Context) error {
+	principal := middlewares.GetPrincipal(c)
+
+	ctx := c.Request().Context()
+	sub, err := se.IStreamUC.Subscribe(ctx, principal)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	defer se.IStreamUC.Unsubscribe(sub)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	heartbeat := time.NewTicker(StreamHeartbeat)
+	defer heartbeat.Stop()
+	recheck := time.NewTicker(StreamRecheck)
+	defer recheck.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+		case <-recheck.C:
+			if err := se.recheck(c, sub); err != nil {
+				// the client reconnects and authenticates again
+				return nil
+			}
+			continue
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return nil
+			}
+			if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+// recheck ends the stream when the caller's credentials are no longer
+// valid and otherwise refreshes the services it receives.
+func (se *StreamEndpoints) recheck(c echo.Context, sub *stream.Subscription) error {
+	ctx := c.Request().Context()
+	principal := middlewares.GetPrincipal(c)
+
+	if principal.APIKeyID != 0 {
+		// revoked, expired or narrowed keys fail or come back with less
+		current, err := se.IAPIKeyUC.Authenticate(ctx, middlewares.APIKey(c))
+		if err != nil {
+			return err
+		}
+		principal = current
+	} else {
+		token, ok := c.Get("user").(*jwt.Token)
+		if !ok {
+			return echo.ErrUnauthorized
+		}
+		claims, ok := token.Claims.(*model.JwtCustomClaims)
+		if !ok {
+			return echo.ErrUnauthorized
+		}
+		if claims.ExpiresAt != nil && time.Now().After(claims.ExpiresAt.Time) {
+			return jwt.ErrTokenExpired
+		}
+		revoked, err := se.ITokenUC.IsRevoked(ctx, claims)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return middlewares.ErrTokenRevoked
+		}
+	}
+
+	return se.IStreamUC.Refresh(ctx, sub, principal)
+}
@@ -0,0 +1,48 @@
+package endpoints
+
+import (
+	"monitoring/internal/delivery/rest/middlewares"
+	. "monitoring/internal/globals"
+	"monitoring/internal/repository"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SystemEndpoints struct {
+	ISystemUC usecase.ISystemUsecase
+}
+
+func NewSystemEndpoints() *SystemEndpoints {
+	return &SystemEndpoints{
+		ISystemUC: &usecase.SystemUsecase{
+			IServicesRepo:     &repository.ServicesRepository{DB: GlobalPG},
+			IUserRepo:         &userrepo.UserRepo{DB: GlobalPG},
+			IErrorReportsRepo: &repository.ErrorReportsRepository{DB: GlobalPG},
+		},
+	}
+}
+
+// Get returns the whole system snapshot.
+func (se *SystemEndpoints) Get(c echo.Context) error {
+	system, err := se.ISystemUC.Get(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, system)
+}
+
+// GetUserSystem returns the snapshot of the services assigned to the caller.
+func (se *SystemEndpoints) GetUserSystem(c echo.Context) error {
+	principal := middlewares.GetPrincipal(c)
+
+	system, err := se.ISystemUC.GetUserSystem(c.Request().Context(), principal.Name, int(principal.Role), principal.UserID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, system)
+}
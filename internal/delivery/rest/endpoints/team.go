@@ -0,0 +1,164 @@
+package endpoints
+
+import (
+	"errors"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TeamsEndpoints struct {
+	ITeamsUC usecase.ITeamsUsecase
+}
+
+func NewTeamsEndpoints() *TeamsEndpoints {
+	return &TeamsEndpoints{
+		ITeamsUC: &usecase.TeamsUsecase{
+			ITeamsRepo: &repository.TeamsRepository{DB: GlobalPG},
+			IAuditUC:   NewAuditUC(),
+		},
+	}
+}
+
+// List returns every team with the ids of its members and services.
+func (te *TeamsEndpoints) List(c echo.Context) error {
+	teams, err := te.ITeamsUC.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"teams": teams,
+	})
+}
+
+// Read returns the team given by the id query parameter.
+func (te *TeamsEndpoints) Read(c echo.Context) error {
+	id, err := strconv.Atoi(c.QueryParam("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "id must be a number"})
+	}
+
+	team, err := te.ITeamsUC.Get(c.Request().Context(), id)
+	if errors.Is(err, usecase.ErrTeamNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, team)
+}
+
+func (te *TeamsEndpoints) Add(c echo.Context) error {
+	var team model.Team
+	if err := c.Bind(&team); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+
+	if err := te.ITeamsUC.Add(c.Request().Context(), team); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Team added",
+	})
+}
+
+func (te *TeamsEndpoints) Update(c echo.Context) error {
+	var team model.Team
+	if err := c.Bind(&team); err != nil || team.ID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+
+	err := te.ITeamsUC.Update(c.Request().Context(), team)
+	if errors.Is(err, usecase.ErrTeamNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Team updated",
+	})
+}
+
+func (te *TeamsEndpoints) Delete(c echo.Context) error {
+	var team model.Team
+	if err := c.Bind(&team); err != nil || team.ID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+
+	if err := te.ITeamsUC.Delete(c.Request().Context(), team.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Team deleted",
+	})
+}
+
+func (te *TeamsEndpoints) AddMember(c echo.Context) error {
+	var member model.TeamMember
+	if err := c.Bind(&member); err != nil || member.TeamID == 0 || member.UserID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "team_id and user_id are required"})
+	}
+
+	err := te.ITeamsUC.AddMember(c.Request().Context(), member)
+	if errors.Is(err, usecase.ErrTeamNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Member added",
+	})
+}
+
+func (te *TeamsEndpoints) RemoveMember(c echo.Context) error {
+	var member model.TeamMember
+	if err := c.Bind(&member); err != nil || member.TeamID == 0 || member.UserID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "team_id and user_id are required"})
+	}
+
+	if err := te.ITeamsUC.RemoveMember(c.Request().Context(), member); err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Member removed",
+	})
+}
+
+func (te *TeamsEndpoints) AddService(c echo.Context) error {
+	var teamService model.TeamService
+	if err := c.Bind(&teamService); err != nil || teamService.TeamID == 0 || teamService.ServiceID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "team_id and service_id are required"})
+	}
+
+	err := te.ITeamsUC.AddService(c.Request().Context(), teamService)
+	if errors.Is(err, usecase.ErrTeamNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Service added to team",
+	})
+}
+
+func (te *TeamsEndpoints) RemoveService(c echo.Context) error {
+	var teamService model.TeamService
+	if err := c.Bind(&teamService); err != nil || teamService.TeamID == 0 || teamService.ServiceID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "team_id and service_id are required"})
+	}
+
+	if err := te.ITeamsUC.RemoveService(c.Request().Context(), teamService); err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Service removed from team",
+	})
+}
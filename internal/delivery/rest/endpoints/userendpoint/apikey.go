@@ -0,0 +1,74 @@
+package userendpoint
+
+import (
+	"monitoring/internal/delivery/rest/middlewares"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/usecase/useruc"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyEndpoint struct {
+	APIKeyUC useruc.IAPIKeyUsecase
+}
+
+func NewAPIKeyEndpoint() *APIKeyEndpoint {
+	return &APIKeyEndpoint{
+		APIKeyUC: NewAPIKeyUC(),
+	}
+}
+
+// NewAPIKeyUC returns the api key usecase shared by the endpoints and the
+// auth middleware.
+func NewAPIKeyUC() *useruc.APIKeyUC {
+	return &useruc.APIKeyUC{
+		IAPIKeyRepo: &userrepo.APIKeyRepo{DB: GlobalPG},
+		IUserRepo:   &userrepo.UserRepo{DB: GlobalPG},
+		IAuditUC:    newAuditUC(),
+	}
+}
+
+func (ae *APIKeyEndpoint) List(c echo.Context) error {
+	keys, err := ae.APIKeyUC.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"api_keys": keys,
+	})
+}
+
+// Add issues a key acting for the given username with the given scopes.
+// The key is only returned in this response.
+func (ae *APIKeyEndpoint) Add(c echo.Context) error {
+	var key model.APIKey
+	if err := c.Bind(&key); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+	key.CreatedBy = middlewares.GetPrincipal(c).Name
+
+	secret, err := ae.APIKeyUC.Create(c.Request().Context(), key)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"key": secret,
+	})
+}
+
+func (ae *APIKeyEndpoint) Revoke(c echo.Context) error {
+	var key model.APIKey
+	if err := c.Bind(&key); err != nil || key.ID == 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+
+	if err := ae.APIKeyUC.Revoke(c.Request().Context(), key.ID); err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "API key revoked",
+	})
+}
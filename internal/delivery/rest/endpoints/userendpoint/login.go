@@ -5,7 +5,6 @@ import (
 	"monitoring/internal/repository/userrepo"
 	"monitoring/internal/usecase/useruc"
 	"net/http"
-	"time"
 
 	. "monitoring/internal/globals"
 
@@ -17,13 +16,15 @@ type LoginUserEndpoint struct {
 	Name string `json:"name,omitempty"`
 	Role int    `json:"role,omitempty"`
 	jwt.RegisteredClaims
-	LoginUC useruc.ILogin `json:"login_uc,omitempty"`
+	LoginUC useruc.ILogin        `json:"login_uc,omitempty"`
+	TokenUC useruc.ITokenUsecase `json:"token_uc,omitempty"`
 }
 
 func NewLoginUserEndpoint() *LoginUserEndpoint {
 	var loginuc useruc.LoginUC = useruc.LoginUC{ILoginRepo: &userrepo.LoginRepo{DB: GlobalPG}}
 	return &LoginUserEndpoint{
 		LoginUC: &loginuc,
+		TokenUC: NewTokenUC(),
 	}
 }
 
@@ -45,26 +46,9 @@ func (le *LoginUserEndpoint) Login(c echo.Context) error {
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
 		}
 	}
-	var mapclaim jwt.MapClaims
-	if loginuc.Role == 1 {
-		mapclaim = jwt.MapClaims{
-			"name": loginuc.Username,
-			"role": loginuc.Role,
-			"exp":  time.Now().Add(time.Hour * 72).Unix(),
-		}
-	} else {
-		mapclaim = jwt.MapClaims{
-			"name": loginuc.Username,
-			"role": loginuc.Role,
-			"exp":  time.Now().Add(time.Hour * 72).Unix(),
-		}
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mapclaim)
-	t, err := token.SignedString([]byte("secret"))
+	tokens, err := le.TokenUC.Issue(c.Request().Context(), loginuc.ID, loginuc.Username, loginuc.Role)
 	if err != nil {
-		return err
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
 	}
-	return c.JSON(http.StatusOK, echo.Map{
-		"token": t,
-	})
+	return c.JSON(http.StatusOK, tokens)
 }
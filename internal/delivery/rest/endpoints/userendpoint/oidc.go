@@ -0,0 +1,98 @@
+package userendpoint
+
+import (
+	"errors"
+	. "monitoring/internal/globals"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/usecase/useruc"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// oidcCookie carries state and nonce from the login to the callback.
+const oidcCookie = "oidc_state"
+
+type OIDCEndpoint struct {
+	OIDCUC useruc.IOIDCUsecase
+}
+
+func NewOIDCEndpoint() (*OIDCEndpoint, error) {
+	cfg := GlobalConfig.OIDC
+	mapping, err := useruc.ParseRoleMapping(cfg.RoleMapping)
+	if err != nil {
+		return nil, err
+	}
+	return &OIDCEndpoint{
+		OIDCUC: &useruc.OIDCUC{
+			IOIDCRepo:     &userrepo.OIDCRepo{DB: GlobalPG},
+			IUserRepo:     &userrepo.UserRepo{DB: GlobalPG},
+			ITokenUC:      NewTokenUC(),
+			IAuditUC:      newAuditUC(),
+			Issuer:        cfg.Issuer,
+			ClientID:      cfg.ClientID,
+			ClientSecret:  cfg.ClientSecret,
+			RedirectURL:   cfg.RedirectURL,
+			Scopes:        cfg.Scopes,
+			UsernameClaim: cfg.UsernameClaim,
+			RoleClaim:     cfg.RoleClaim,
+			RoleMapping:   mapping,
+			DefaultRole:   cfg.DefaultRole,
+		},
+	}, nil
+}
+
+// Login redirects to the provider's login page.
+func (oe *OIDCEndpoint) Login(c echo.Context) error {
+	url, state, nonce, err := oe.OIDCUC.AuthURL(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, echo.Map{"error": "identity provider unavailable"})
+	}
+	c.SetCookie(oe.cookie(c, state+"."+nonce, 10*time.Minute))
+	return c.Redirect(http.StatusFound, url)
+}
+
+// Callback completes the login the provider redirected back from and
+// returns the project's tokens.
+func (oe *OIDCEndpoint) Callback(c echo.Context) error {
+	if reason := c.QueryParam("error"); reason != "" {
+		return c.JSON(http.StatusUnauthorized, echo.Map{"error": reason, "description": c.QueryParam("error_description")})
+	}
+
+	cookie, err := c.Cookie(oidcCookie)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "login expired"})
+	}
+	c.SetCookie(oe.cookie(c, "", -1))
+
+	state, nonce, ok := strings.Cut(cookie.Value, ".")
+	code := c.QueryParam("code")
+	if !ok || code == "" || c.QueryParam("state") != state {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid state"})
+	}
+
+	tokens, err := oe.OIDCUC.Callback(c.Request().Context(), code, nonce)
+	switch {
+	case errors.Is(err, useruc.ErrInvalidOIDCLogin):
+		return c.JSON(http.StatusUnauthorized, echo.Map{"error": err.Error()})
+	case errors.Is(err, useruc.ErrOIDCAccessDenied):
+		return c.JSON(http.StatusForbidden, echo.Map{"error": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "internal server error"})
+	}
+	return c.JSON(http.StatusOK, tokens)
+}
+
+func (oe *OIDCEndpoint) cookie(c echo.Context, value string, maxAge time.Duration) *http.Cookie {
+	return &http.Cookie{
+		Name:     oidcCookie,
+		Value:    value,
+		Path:     "/oidc",
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+}
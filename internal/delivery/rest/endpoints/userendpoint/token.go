@@ -0,0 +1,78 @@
+package userendpoint
+
+import (
+	"errors"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/usecase/useruc"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type TokenEndpoint struct {
+	TokenUC useruc.ITokenUsecase
+}
+
+func NewTokenEndpoint() *TokenEndpoint {
+	return &TokenEndpoint{
+		TokenUC: NewTokenUC(),
+	}
+}
+
+// NewTokenUC returns the token usecase shared by login, logout and the jwt
+// middleware.
+func NewTokenUC() *useruc.TokenUC {
+	return &useruc.TokenUC{
+		ITokenRepo: &userrepo.TokenRepo{DB: GlobalPG},
+		IUserRepo:  &userrepo.UserRepo{DB: GlobalPG},
+		Keys:       GlobalJWTKeys,
+		AccessTTL:  GlobalConfig.JWT.AccessTTL,
+		RefreshTTL: GlobalConfig.JWT.RefreshTTL,
+	}
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token.
+func (te *TokenEndpoint) Refresh(c echo.Context) error {
+	var req refreshRequest
+	if err := c.Bind(&req); err != nil || req.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+
+	tokens, err := te.TokenUC.Refresh(c.Request().Context(), req.RefreshToken)
+	if errors.Is(err, useruc.ErrInvalidRefreshToken) {
+		return c.JSON(http.StatusUnauthorized, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "internal server error"})
+	}
+	return c.JSON(http.StatusOK, tokens)
+}
+
+// Logout revokes the caller's access token and the refresh token in the
+// body, or all of the caller's refresh tokens when it is empty.
+func (te *TokenEndpoint) Logout(c echo.Context) error {
+	claims := c.Get("user").(*jwt.Token).Claims.(*model.JwtCustomClaims)
+
+	var req refreshRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid JSON"})
+	}
+
+	err := te.TokenUC.Logout(c.Request().Context(), claims, req.RefreshToken)
+	if errors.Is(err, useruc.ErrInvalidRefreshToken) {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "internal server error"})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Logged out",
+	})
+}
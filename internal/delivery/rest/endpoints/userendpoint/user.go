@@ -3,7 +3,9 @@ package userendpoint
 import (
 	. "monitoring/internal/globals"
 	"monitoring/internal/model"
+	"monitoring/internal/repository"
 	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/usecase"
 	"monitoring/internal/usecase/useruc"
 	"net/http"
 
@@ -19,12 +21,18 @@ type UserEndpoint struct {
 
 func NewUserEndpoint() *UserEndpoint {
 	db := userrepo.UserRepo{DB: GlobalPG}
-	var useruc useruc.UserUsecase = useruc.UserUsecase{IUserRepo: &db}
+	var useruc useruc.UserUsecase = useruc.UserUsecase{IUserRepo: &db, ITokenUC: NewTokenUC(), IAuditUC: newAuditUC()}
 	return &UserEndpoint{
 		UserUC: &useruc,
 	}
 }
 
+func newAuditUC() *usecase.AuditUsecase {
+	return &usecase.AuditUsecase{
+		IAuditRepo: &repository.AuditRepository{DB: GlobalPG},
+	}
+}
+
 func (ue *UserEndpoint) Create(c echo.Context) error {
 	var usr model.UserAuth
 	if err := c.Bind(&usr); err != nil {
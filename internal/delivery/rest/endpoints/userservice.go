@@ -20,6 +20,7 @@ func NewUserServiceEndpoints() *UserServiceEndpoints {
 			UserServiceRepo: &repository.UserServiceRepository{
 				DB: GlobalPG,
 			},
+			IAuditUC: NewAuditUC(),
 		},
 	}
 
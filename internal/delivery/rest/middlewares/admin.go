@@ -11,40 +11,6 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-// echo middleware to check jwt token
-func IsAdminUser(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		if c.Get("user") != nil {
-			tokenString := c.Get("user").(*jwt.Token)
-			token, err := jwt.Parse(tokenString.Raw, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-				}
-				return []byte("secret"), nil
-			})
-			if err != nil {
-				return echo.ErrInternalServerError
-			}
-			jwtToken := token.Claims.(jwt.MapClaims)
-			if _, ok := jwtToken["role"]; !ok {
-				return echo.ErrForbidden
-			}
-			//check if jwtToken["role"] is float64
-			if _, ok := jwtToken["role"].(float64); !ok {
-				return echo.ErrForbidden
-			}
-			role := int(jwtToken["role"].(float64))
-			if role == 1 {
-				return next(c)
-			} else {
-				return echo.ErrForbidden
-			}
-		} else {
-			return echo.ErrForbidden
-		}
-	}
-}
-
 func MakeAdminUser(c echo.Context) (err error) {
 	var count int
 	row, err := GlobalPG.QueryContext(c.Request().Context(), "select count(*) as count from Users")
@@ -87,7 +53,7 @@ func MakeAdminUser(c echo.Context) (err error) {
 
 func MakeToken(usname string, role int, expiredays int64) (tokenString string, err error) {
 	type MyCustomClaims struct {
-		Sm   string `json:"sm"`
+		Name string `json:"name"`
 		Role int    `json:"role"`
 		jwt.RegisteredClaims
 	}
@@ -96,14 +62,13 @@ func MakeToken(usname string, role int, expiredays int64) (tokenString string, e
 		usname,
 		role,
 		jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour * time.Duration(expiredays))),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Sign and get the complete encoded token as a string using the secret
-	tokenString, err = token.SignedString([]byte("secret"))
+	// Sign and get the complete encoded token as a string using the active key
+	tokenString, err = GlobalJWTKeys.Sign(claims)
 	if err != nil {
 		return "", err
 	}
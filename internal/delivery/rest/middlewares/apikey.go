@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"errors"
+	"monitoring/internal/usecase/useruc"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const apiKeyScheme = "ApiKey "
+
+// APIKeyAuth authenticates requests sent with "Authorization: ApiKey <key>"
+// and sets their principal, other requests are left to the jwt middleware.
+func APIKeyAuth(keys useruc.IAPIKeyUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			secret := APIKey(c)
+			if secret == "" {
+				return next(c)
+			}
+
+			principal, err := keys.Authenticate(c.Request().Context(), secret)
+			if errors.Is(err, useruc.ErrInvalidAPIKey) {
+				return c.JSON(http.StatusUnauthorized, echo.Map{"error": err.Error()})
+			}
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+			}
+			setPrincipal(c, principal)
+			return next(c)
+		}
+	}
+}
+
+// APIKey returns the key the request was sent with, empty when it has none.
+func APIKey(c echo.Context) string {
+	auth := c.Request().Header.Get(echo.HeaderAuthorization)
+	if len(auth) <= len(apiKeyScheme) || !strings.EqualFold(auth[:len(apiKeyScheme)], apiKeyScheme) {
+		return ""
+	}
+	return strings.TrimSpace(auth[len(apiKeyScheme):])
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"errors"
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/usecase/useruc"
+
+	"github.com/golang-jwt/jwt/v5"
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4"
+)
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// NewJWTConfig returns the echojwt config verifying tokens against the
+// configured keys and rejecting revoked ones. Requests already
+// authenticated, e.g. with an API key, are skipped.
+func NewJWTConfig(tokens useruc.ITokenUsecase) echojwt.Config {
+	return echojwt.Config{
+		Skipper: func(c echo.Context) bool {
+			return GetPrincipal(c) != nil
+		},
+		ParseTokenFunc: func(c echo.Context, auth string) (interface{}, error) {
+			claims := new(model.JwtCustomClaims)
+			token, err := jwt.ParseWithClaims(auth, claims, GlobalJWTKeys.Keyfunc)
+			if err != nil {
+				return nil, err
+			}
+
+			revoked, err := tokens.IsRevoked(c.Request().Context(), claims)
+			if err != nil {
+				return nil, err
+			}
+			if revoked {
+				return nil, ErrTokenRevoked
+			}
+			return token, nil
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	. "monitoring/internal/globals"
+	"monitoring/internal/model"
+	"monitoring/internal/repository/userrepo"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const principalKey = "principal"
+
+// SetPrincipal turns the claims of the token validated by echojwt into the
+// principal of the request.
+func SetPrincipal(next echo.HandlerFunc) echo.HandlerFunc {
+	users := &userrepo.UserRepo{DB: GlobalPG}
+	return func(c echo.Context) error {
+		if GetPrincipal(c) != nil {
+			return next(c)
+		}
+		token, ok := c.Get("user").(*jwt.Token)
+		if !ok {
+			return echo.ErrUnauthorized
+		}
+		claims, ok := token.Claims.(*model.JwtCustomClaims)
+		if !ok {
+			return echo.ErrUnauthorized
+		}
+
+		userId, err := users.GetUsrId(c.Request().Context(), claims.Name)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		}
+		if userId == 0 {
+			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "unknown user"})
+		}
+		setPrincipal(c, model.NewPrincipal(userId, claims.Name, model.AccessLevel(claims.RoleId)))
+		return next(c)
+	}
+}
+
+// setPrincipal stores the caller on the echo context for the handlers and
+// on the request context for the usecases.
+func setPrincipal(c echo.Context, principal *model.Principal) {
+	c.Set(principalKey, principal)
+	req := c.Request()
+	c.SetRequest(req.WithContext(model.ContextWithPrincipal(req.Context(), principal)))
+}
+
+// GetPrincipal returns the caller set by the auth middlewares, nil on
+// routes without authentication.
+func GetPrincipal(c echo.Context) *model.Principal {
+	principal, _ := c.Get(principalKey).(*model.Principal)
+	return principal
+}
+
+// RequirePermission rejects callers that lack any of perms.
+func RequirePermission(perms ...model.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal := GetPrincipal(c)
+			if principal == nil {
+				return echo.ErrUnauthorized
+			}
+			for _, perm := range perms {
+				if !principal.Can(perm) {
+					return c.JSON(http.StatusForbidden, echo.Map{"error": "missing permission " + string(perm)})
+				}
+			}
+			return next(c)
+		}
+	}
+}
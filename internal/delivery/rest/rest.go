@@ -1,19 +1,21 @@
 package internal
 
 import (
+	"context"
 	"monitoring/config"
 	// "monitoring/internal/delivery/rest/endpoints"
 	"monitoring/internal/delivery/rest/endpoints"
 	"monitoring/internal/delivery/rest/endpoints/userendpoint"
 	"monitoring/internal/delivery/rest/middlewares"
 	"monitoring/internal/model"
+	"monitoring/internal/stream"
 
-	// . "monitoring/internal/globals"
+	. "monitoring/internal/globals"
+	"monitoring/internal/metrics"
 
 	"monitoring/pkg/postgres"
 	"net/http"
 
-	"github.com/golang-jwt/jwt/v5"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 )
@@ -31,39 +33,123 @@ func New() (r *Rest, err error) {
 		e: e,
 	}
 
+	if GlobalConfig.Metrics.Enabled {
+		e.Use(metrics.Middleware())
+	}
+
+	// Configure middleware with the custom claims type
+	config := middlewares.NewJWTConfig(userendpoint.NewTokenUC())
+
 	loginEndpoint := userendpoint.NewLoginUserEndpoint()
 	e.POST("/login", loginEndpoint.Login).Name = "login"
 
+	tokens := userendpoint.NewTokenEndpoint()
+	e.POST("/token/refresh", tokens.Refresh).Name = "token-refresh"
+	e.POST("/logout", tokens.Logout, echojwt.WithConfig(config)).Name = "logout"
+
+	if GlobalConfig.OIDC.Enabled {
+		oidc, err := userendpoint.NewOIDCEndpoint()
+		if err != nil {
+			return nil, err
+		}
+		e.GET("/oidc/login", oidc.Login).Name = "oidc-login"
+		e.GET("/oidc/callback", oidc.Callback).Name = "oidc-callback"
+	}
+
+	if GlobalConfig.Status.Enabled {
+		status := endpoints.NewStatusPageEndpoints()
+		e.GET("/status", status.GetHTML).Name = "status"
+		e.GET("/status/json", status.GetJSON).Name = "status-json"
+	}
+
+	auth := []echo.MiddlewareFunc{
+		middlewares.APIKeyAuth(userendpoint.NewAPIKeyUC()),
+		echojwt.WithConfig(config),
+		middlewares.SetPrincipal,
+	}
 	restericted := e.Group("/panel")
-	// Configure middleware with the custom claims type
-	config := echojwt.Config{
-		NewClaimsFunc: func(c echo.Context) jwt.Claims {
-			return new(model.JwtCustomClaims)
-		},
-		SigningKey: []byte("secret"),
+	restericted.Use(auth...)
+
+	serviceRead := middlewares.RequirePermission(model.PermServiceRead)
+	serviceWrite := middlewares.RequirePermission(model.PermServiceWrite)
+	incidentWrite := middlewares.RequirePermission(model.PermIncidentWrite)
+	systemRead := middlewares.RequirePermission(model.PermSystemRead)
+	userAdmin := middlewares.RequirePermission(model.PermUserAdmin)
+
+	if GlobalConfig.Metrics.Enabled {
+		// metrics name every service, including the non-public ones
+		e.GET("/metrics", metrics.Handler, append(auth, systemRead)...).Name = "metrics"
 	}
-	restericted.Use(echojwt.WithConfig(config))
-	restericted.Use(middlewares.IsAdminUser)
 
 	user := userendpoint.NewUserEndpoint()
-	restericted.Use()
-	restericted.POST("/user/create", user.Create)
-	restericted.POST("/user/read", user.Read)
-	restericted.GET("/user/readall", user.ReadAll)
-	restericted.POST("/user/update", user.Update)
-	restericted.POST("/user/delete", user.Delete)
+	restericted.POST("/user/create", user.Create, userAdmin)
+	restericted.POST("/user/read", user.Read, userAdmin)
+	restericted.GET("/user/readall", user.ReadAll, userAdmin)
+	restericted.POST("/user/update", user.Update, userAdmin)
+	restericted.POST("/user/delete", user.Delete, userAdmin)
+
+	apiKeys := userendpoint.NewAPIKeyEndpoint()
+	restericted.GET("/apikeys", apiKeys.List, userAdmin)
+	restericted.POST("/apikeys/add", apiKeys.Add, userAdmin)
+	restericted.POST("/apikeys/revoke", apiKeys.Revoke, userAdmin)
 
 	service := endpoints.NewServicesEndpoints()
-	restericted.GET("/service/getservices", service.GetUserServices)
-	restericted.GET("/service/getservice", service.GetUserService)
-	restericted.POST("/service/add", service.AddService)
-	restericted.POST("/service/delete", service.DeleteService)
+	restericted.GET("/service/getservices", service.GetUserServices, serviceRead)
+	restericted.GET("/service/getservice", service.GetUserService, serviceRead)
+	restericted.GET("/service/uptime", service.GetServiceUptime, serviceRead)
+	restericted.GET("/service/stats", service.GetServiceStats, serviceRead)
+	restericted.POST("/service/add", service.AddService, serviceWrite)
+	restericted.POST("/service/delete", service.DeleteService, serviceWrite)
 
 	userServices := endpoints.NewUserServiceEndpoints()
-	restericted.GET("/user_services/readall", userServices.List)
-	restericted.GET("/user_services/read", userServices.GetUserService)
-	restericted.POST("/user_services/add", userServices.Add)
-	restericted.POST("/user_services/delete", userServices.Delete)
+	restericted.GET("/user_services/readall", userServices.List, userAdmin)
+	restericted.GET("/user_services/read", userServices.GetUserService, userAdmin)
+	restericted.POST("/user_services/add", userServices.Add, userAdmin)
+	restericted.POST("/user_services/delete", userServices.Delete, userAdmin)
+
+	teams := endpoints.NewTeamsEndpoints()
+	restericted.GET("/teams", teams.List, userAdmin)
+	restericted.GET("/teams/read", teams.Read, userAdmin)
+	restericted.POST("/teams/add", teams.Add, userAdmin)
+	restericted.POST("/teams/update", teams.Update, userAdmin)
+	restericted.POST("/teams/delete", teams.Delete, userAdmin)
+	restericted.POST("/teams/members/add", teams.AddMember, userAdmin)
+	restericted.POST("/teams/members/delete", teams.RemoveMember, userAdmin)
+	restericted.POST("/teams/services/add", teams.AddService, userAdmin)
+	restericted.POST("/teams/services/delete", teams.RemoveService, userAdmin)
+
+	audit := endpoints.NewAuditEndpoints()
+	restericted.GET("/audit", audit.List, userAdmin)
+
+	errorReports := endpoints.NewErrorReportsEndpoints()
+	restericted.GET("/errors", errorReports.List, systemRead)
+
+	system := endpoints.NewSystemEndpoints()
+	restericted.GET("/system", system.Get, systemRead)
+	restericted.GET("/system/me", system.GetUserSystem, serviceRead)
+
+	certificates := endpoints.NewCertificatesEndpoints()
+	restericted.GET("/certificates", certificates.List, systemRead)
+
+	alertRules := endpoints.NewAlertRulesEndpoints()
+	restericted.GET("/alerts/rules", alertRules.List, systemRead)
+	restericted.POST("/alerts/rules/add", alertRules.Add, serviceWrite)
+	restericted.POST("/alerts/rules/update", alertRules.Update, serviceWrite)
+	restericted.POST("/alerts/rules/delete", alertRules.Delete, serviceWrite)
+
+	incidents := endpoints.NewIncidentsEndpoints()
+	restericted.GET("/incidents", incidents.List, serviceRead)
+	restericted.GET("/incidents/timeline", incidents.Timeline, serviceRead)
+	restericted.POST("/incidents/acknowledge", incidents.Acknowledge, incidentWrite)
+	restericted.POST("/incidents/comment", incidents.Comment, incidentWrite)
+
+	maintenance := endpoints.NewMaintenanceEndpoints()
+	restericted.GET("/maintenance", maintenance.List, systemRead)
+	restericted.POST("/maintenance/add", maintenance.Add, serviceWrite)
+	restericted.POST("/maintenance/delete", maintenance.Delete, serviceWrite)
+
+	liveStream := endpoints.NewStreamEndpoints()
+	restericted.GET("/stream", liveStream.Stream, serviceRead)
 
 	e.GET("/demo", demo)
 	e.GET("/test", test, echojwt.WithConfig(config))
@@ -77,6 +163,12 @@ func (r Rest) Start(address string) (err error) {
 	return
 }
 
+func (r Rest) Shutdown(ctx context.Context) error {
+	// streams never finish on their own
+	stream.Default.Close()
+	return r.e.Shutdown(ctx)
+}
+
 func test(c echo.Context) error {
 	return c.String(http.StatusOK, "test")
 }
@@ -2,8 +2,10 @@ package globals
 
 import (
 	"monitoring/config"
+	"monitoring/pkg/jwtkeys"
 	"monitoring/pkg/postgres"
 )
 
 var GlobalPG postgres.IPostgres
 var GlobalConfig config.Config
+var GlobalJWTKeys *jwtkeys.KeySet
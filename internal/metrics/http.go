@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	httpRequests = Default.NewCounterVec("monitoring_http_requests_total",
+		"HTTP requests handled by the API.", "method", "route", "status")
+	httpDuration = Default.NewHistogramVec("monitoring_http_request_duration_seconds",
+		"Time spent handling HTTP requests.", nil, "method", "route")
+)
+
+// Middleware counts and times every request by its registered route, so
+// path parameters don't create new series.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// let echo write the error first so its status is counted
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			method := c.Request().Method
+			httpRequests.Inc(method, route, strconv.Itoa(c.Response().Status))
+			httpDuration.Observe(time.Since(start).Seconds(), method, route)
+
+			return nil
+		}
+	}
+}
+
+// Handler serves the default registry in the text exposition format.
+func Handler(c echo.Context) error {
+	var buf bytes.Buffer
+	if err := Default.Write(&buf); err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.Blob(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", buf.Bytes())
+}
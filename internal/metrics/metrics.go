@@ -0,0 +1,319 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds in seconds used by histograms
+// created without their own buckets.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the registry served on /metrics.
+var Default = NewRegistry()
+
+type collector interface {
+	write(w io.Writer) error
+}
+
+// Registry holds metrics and writes them in the Prometheus text exposition
+// format.
+type Registry struct {
+	mu         sync.Mutex
+	names      []string
+	collectors map[string]collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: map[string]collector{}}
+}
+
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.collectors[name]; ok {
+		panic(fmt.Sprintf("metrics: %q registered twice", name))
+	}
+	r.names = append(r.names, name)
+	r.collectors[name] = c
+}
+
+// Write writes every registered metric in registration order.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	collectors := make([]collector, 0, len(r.names))
+	for _, name := range r.names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mu.Unlock()
+
+	for _, c := range collectors {
+		if err := c.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// desc is the name, help and label names shared by every metric type.
+type desc struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (d desc) header(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, d.kind)
+	return err
+}
+
+func (d desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelString formats the label pairs of key, with extra appended last.
+func (d desc) labelString(key string, extra ...string) string {
+	var pairs []string
+	if len(d.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, fmt.Sprintf(`%s="%s"`, d.labels[i], escapeLabel(value)))
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[i], escapeLabel(extra[i+1])))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// vec is a set of float values by label values, used by gauges and counters.
+type vec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (v *vec) add(values []string, delta float64) {
+	key := v.key(values)
+	v.mu.Lock()
+	v.values[key] += delta
+	v.mu.Unlock()
+}
+
+func (v *vec) set(values []string, value float64) {
+	key := v.key(values)
+	v.mu.Lock()
+	v.values[key] = value
+	v.mu.Unlock()
+}
+
+func (v *vec) delete(values []string) {
+	key := v.key(values)
+	v.mu.Lock()
+	delete(v.values, key)
+	v.mu.Unlock()
+}
+
+// deleteMatching removes every series whose label has value.
+func (v *vec) deleteMatching(label, value string) {
+	index := -1
+	for i, l := range v.labels {
+		if l == label {
+			index = i
+		}
+	}
+	if index < 0 {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for key := range v.values {
+		if strings.Split(key, "\xff")[index] == value {
+			delete(v.values, key)
+		}
+	}
+}
+
+func (v *vec) write(w io.Writer) error {
+	if err := v.header(w); err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	keys := sortedKeys(v.values)
+	samples := make([]float64, len(keys))
+	for i, key := range keys {
+		samples[i] = v.values[key]
+	}
+	v.mu.Unlock()
+
+	for i, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", v.name, v.labelString(key), formatFloat(samples[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CounterVec is a monotonically increasing value per label values.
+type CounterVec struct{ vec }
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec{desc: desc{name, help, "counter", labels}, values: map[string]float64{}}}
+	r.register(name, c)
+	return c
+}
+
+func (c *CounterVec) Inc(values ...string) {
+	c.add(values, 1)
+}
+
+// DeleteMatching removes every series whose label has value.
+func (c *CounterVec) DeleteMatching(label, value string) {
+	c.deleteMatching(label, value)
+}
+
+// GaugeVec is a value per label values that can go up and down.
+type GaugeVec struct{ vec }
+
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{vec{desc: desc{name, help, "gauge", labels}, values: map[string]float64{}}}
+	r.register(name, g)
+	return g
+}
+
+func (g *GaugeVec) Set(value float64, values ...string) {
+	g.set(values, value)
+}
+
+// Delete removes the series of the given label values.
+func (g *GaugeVec) Delete(values ...string) {
+	g.delete(values)
+}
+
+// DeleteMatching removes every series whose label has value.
+func (g *GaugeVec) DeleteMatching(label, value string) {
+	g.deleteMatching(label, value)
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec counts observations into cumulative buckets per label values.
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogram
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	h := &HistogramVec{desc: desc{name, help, "histogram", labels}, buckets: buckets, values: map[string]*histogram{}}
+	r.register(name, h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hist, ok := h.values[key]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hist
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+func (h *HistogramVec) write(w io.Writer) error {
+	if err := h.header(w); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	keys := sortedKeys(h.values)
+	hists := make([]histogram, len(keys))
+	for i, key := range keys {
+		hist := h.values[key]
+		hists[i] = histogram{counts: append([]uint64(nil), hist.counts...), count: hist.count, sum: hist.sum}
+	}
+	h.mu.Unlock()
+
+	for i, key := range keys {
+		hist := hists[i]
+		for j, bound := range h.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(key, "le", formatFloat(bound)), hist.counts[j]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			h.name, h.labelString(key, "le", "+Inf"), hist.count,
+			h.name, h.labelString(key), formatFloat(hist.sum),
+			h.name, h.labelString(key), hist.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func write(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	if err := r.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("requests_total", "Requests.", "path", "code")
+	temperature := r.NewGaugeVec("temperature", "Line one\nline \\two.")
+	r.NewGaugeVec("empty", "No series.", "a")
+
+	requests.Inc("/b", "200")
+	requests.Inc("/a", "500")
+	requests.Inc("/a", "500")
+	temperature.Set(-1.5)
+
+	want := `# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{path="/a",code="500"} 2
+requests_total{path="/b",code="200"} 1
+# HELP temperature Line one\nline \\two.
+# TYPE temperature gauge
+temperature -1.5
+# HELP empty No series.
+# TYPE empty gauge
+`
+	if got := write(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGaugeVec("g", "G.", "name")
+	g.Set(1, "a \"quoted\"\\path\nnext")
+
+	want := `g{name="a \"quoted\"\\path\nnext"} 1`
+	if got := write(t, r); !strings.Contains(got, want+"\n") {
+		t.Errorf("got\n%s\nwant a line\n%s", got, want)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("latency_seconds", "Latency.", []float64{1, 0.5}, "route")
+	h.Observe(0.25, "/")
+	h.Observe(0.5, "/")
+	h.Observe(0.75, "/")
+	h.Observe(3, "/")
+
+	want := `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/",le="0.5"} 2
+latency_seconds_bucket{route="/",le="1"} 3
+latency_seconds_bucket{route="/",le="+Inf"} 4
+latency_seconds_sum{route="/"} 4.5
+latency_seconds_count{route="/"} 4
+`
+	if got := write(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDefaultBuckets(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("h", "H.", nil)
+	h.Observe(20)
+
+	got := write(t, r)
+	if n := strings.Count(got, "h_bucket{"); n != len(DefaultBuckets)+1 {
+		t.Errorf("%d buckets, want %d", n, len(DefaultBuckets)+1)
+	}
+	for _, line := range []string{`h_bucket{le="10"} 0`, `h_bucket{le="+Inf"} 1`, "h_sum 20", "h_count 1"} {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("missing %q in\n%s", line, got)
+		}
+	}
+}
+
+func TestDeleteMatching(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGaugeVec("g", "G.", "service", "type")
+	g.Set(1, "a", "http")
+	g.Set(1, "b", "http")
+	g.DeleteMatching("service", "a")
+
+	got := write(t, r)
+	if strings.Contains(got, `service="a"`) || !strings.Contains(got, `service="b"`) {
+		t.Errorf("got\n%s", got)
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{v: 0, want: "0"},
+		{v: 0.005, want: "0.005"},
+		{v: 1e21, want: "1e+21"},
+		{v: math.Inf(1), want: "+Inf"},
+		{v: math.Inf(-1), want: "-Inf"},
+	}
+	for _, tt := range tests {
+		if got := formatFloat(tt.v); got != tt.want {
+			t.Errorf("formatFloat(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"sync"
+)
+
+var (
+	serviceUp = Default.NewGaugeVec("monitoring_service_up",
+		"Whether the last check of the service succeeded.", "service", "type")
+	serviceLatency = Default.NewGaugeVec("monitoring_service_latency_seconds",
+		"Latency of the last check of the service.", "service", "type")
+	serviceErrorEstimate = Default.NewGaugeVec("monitoring_service_error_estimate",
+		"Rolling error rate estimate of the service in percent.", "service", "type")
+	serviceChecks = Default.NewCounterVec("monitoring_service_checks_total",
+		"Checks run against the service.", "service", "type")
+	serviceFailures = Default.NewCounterVec("monitoring_service_check_failures_total",
+		"Failed checks of the service.", "service", "type")
+)
+
+var serviceVecs = []*vec{
+	&serviceUp.vec, &serviceLatency.vec, &serviceErrorEstimate.vec,
+	&serviceChecks.vec, &serviceFailures.vec,
+}
+
+// ProbeRecorder exports every check result as service metrics. It remembers
+// the labels last exported per service so the old series go away when a
+// service is renamed or its check type changes.
+type ProbeRecorder struct {
+	mu     sync.Mutex
+	labels map[int][2]string
+}
+
+func NewProbeRecorder() *ProbeRecorder {
+	return &ProbeRecorder{labels: map[int][2]string{}}
+}
+
+func (pr *ProbeRecorder) Record(ctx context.Context, service model.Service, result model.CheckResult) error {
+	checkType := model.CheckHTTP
+	if service.Type != nil && *service.Type != "" {
+		checkType = *service.Type
+	}
+	labels := []string{result.ServiceName, checkType}
+
+	pr.mu.Lock()
+	if prev, ok := pr.labels[service.ID]; ok && prev != [2]string{labels[0], labels[1]} {
+		for _, v := range serviceVecs {
+			v.delete(prev[:])
+		}
+	}
+	pr.labels[service.ID] = [2]string{labels[0], labels[1]}
+	pr.mu.Unlock()
+
+	up := 0.0
+	if result.Success {
+		up = 1
+	} else {
+		serviceFailures.Inc(labels...)
+	}
+	serviceChecks.Inc(labels...)
+	serviceUp.Set(up, labels...)
+	serviceLatency.Set(float64(result.Latency)/1000, labels...)
+	serviceErrorEstimate.Set(result.ErrorEstimate, labels...)
+
+	return nil
+}
+
+// Forget stops exporting the series of a removed service.
+func (pr *ProbeRecorder) Forget(service model.Service) {
+	pr.mu.Lock()
+	prev, ok := pr.labels[service.ID]
+	delete(pr.labels, service.ID)
+	pr.mu.Unlock()
+
+	for _, v := range serviceVecs {
+		if ok {
+			v.delete(prev[:])
+		}
+		if service.Name != nil {
+			v.deleteMatching("service", *service.Name)
+		}
+	}
+}
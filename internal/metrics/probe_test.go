@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestProbeRecorderForgetsStaleSeries(t *testing.T) {
+	name, checkType := "probe-test", model.CheckHTTP
+	service := model.Service{ID: 1, Name: &name, Type: &checkType}
+	result := model.CheckResult{ServiceID: 1, ServiceName: name, Success: true}
+
+	pr := NewProbeRecorder()
+	if err := pr.Record(context.Background(), service, result); err != nil {
+		t.Fatal(err)
+	}
+	if got := write(t, Default); !strings.Contains(got, `service="probe-test",type="http"`) {
+		t.Fatalf("no http series in\n%s", got)
+	}
+
+	tcp := model.CheckTCP
+	service.Type = &tcp
+	pr.Record(context.Background(), service, result)
+	got := write(t, Default)
+	if strings.Contains(got, `service="probe-test",type="http"`) {
+		t.Errorf("http series kept after the type changed:\n%s", got)
+	}
+	if !strings.Contains(got, `monitoring_service_checks_total{service="probe-test",type="tcp"} 1`) {
+		t.Errorf("no tcp series in\n%s", got)
+	}
+
+	pr.Forget(service)
+	if got := write(t, Default); strings.Contains(got, `service="probe-test"`) {
+		t.Errorf("series kept after Forget:\n%s", got)
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+type AlertRuleKind string
+
+const (
+	// fires after Threshold failed checks in a row
+	RuleConsecutiveFailures AlertRuleKind = "consecutive_failures"
+	// fires when more than Threshold percent of the checks in the window failed
+	RuleErrorRate AlertRuleKind = "error_rate"
+	// fires when the p95 latency in the window is above Threshold milliseconds
+	RuleLatencyP95 AlertRuleKind = "latency_p95"
+)
+
+type AlertRule struct {
+	ID          int           `json:"id,omitempty"`
+	ServiceID   int           `json:"service_id,omitempty"`
+	ServiceName string        `json:"service_name,omitempty"`
+	Kind        AlertRuleKind `json:"kind"`
+	Threshold   float64       `json:"threshold"`
+	Window      int           `json:"window_seconds,omitempty"`
+	Enabled     bool          `json:"enabled"`
+	Firing      bool          `json:"firing"`
+	ChangedAt   *time.Time    `json:"changed_at,omitempty"`
+}
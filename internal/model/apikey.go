@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// APIKey lets machine clients act for a user, limited to its scopes.
+type APIKey struct {
+	ID         int          `json:"id,omitempty"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix,omitempty"`
+	UserID     int          `json:"user_id,omitempty"`
+	Username   string       `json:"username"`
+	Scopes     []Permission `json:"scopes"`
+	CreatedBy  string       `json:"created_by,omitempty"`
+	CreatedAt  time.Time    `json:"created_at,omitempty"`
+	ExpiresAt  *time.Time   `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time   `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time   `json:"last_used_at,omitempty"`
+}
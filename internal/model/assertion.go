@@ -0,0 +1,31 @@
+package model
+
+type AssertionType string
+
+const (
+	// the response status must be one of Codes
+	AssertStatus AssertionType = "status"
+	// the body must contain Value
+	AssertBodyContains AssertionType = "body_contains"
+	// the body must match the regular expression Value
+	AssertBodyRegex AssertionType = "body_regex"
+	// the JSON body must hold Value at Path, e.g. "$.status" or "$.items[0].id"
+	AssertJSONPathEquals AssertionType = "json_path_equals"
+	// the JSON body must have something at Path
+	AssertJSONPathExists AssertionType = "json_path_exists"
+	// the response must have header Name, equal to Value when it's set
+	AssertHeader AssertionType = "header"
+	// the check must take at most Max milliseconds
+	AssertMaxLatency AssertionType = "max_latency"
+)
+
+// Assertion describes one property of a healthy response. Which fields are
+// used depends on Type.
+type Assertion struct {
+	Type  AssertionType `json:"type"`
+	Codes []int         `json:"codes,omitempty"`
+	Path  string        `json:"path,omitempty"`
+	Name  string        `json:"name,omitempty"`
+	Value interface{}   `json:"value,omitempty"`
+	Max   int64         `json:"max,omitempty"`
+}
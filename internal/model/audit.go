@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+const (
+	AuditTargetUser        = "user"
+	AuditTargetService     = "service"
+	AuditTargetUserService = "user_service"
+	AuditTargetTeam        = "team"
+	AuditTargetAPIKey      = "api_key"
+)
+
+// AuditEvent records an administrative change. Before and After hold only
+// the fields that changed, Before is empty for creations and After for
+// deletions.
+type AuditEvent struct {
+	ID         int64           `json:"id,omitempty"`
+	ActorID    int             `json:"actor_id,omitempty"`
+	Actor      string          `json:"actor"`
+	APIKeyID   *int            `json:"api_key_id,omitempty"`
+	Action     AuditAction     `json:"action"`
+	TargetType string          `json:"target_type"`
+	Target     string          `json:"target"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditFilter struct {
+	Actor      string
+	Action     AuditAction
+	TargetType string
+	Target     string
+	From       time.Time
+	To         time.Time
+	Limit      int
+	Offset     int
+}
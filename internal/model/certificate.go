@@ -0,0 +1,28 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// Certificate describes the TLS certificate chain presented by a service.
+// NotAfter and DaysLeft refer to the certificate of the chain that expires
+// first, the other fields to the leaf.
+type Certificate struct {
+	ServiceID     int       `json:"service_id,omitempty"`
+	ServiceName   string    `json:"service_name,omitempty"`
+	Subject       string    `json:"subject,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	SANs          []string  `json:"sans,omitempty"`
+	NotBefore     time.Time `json:"not_before"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysLeft      int       `json:"days_left"`
+	HostnameMatch bool      `json:"hostname_match"`
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+// CertificateDaysLeft returns the whole days until notAfter. It rounds down,
+// so certificates are at -1 days once they expire.
+func CertificateDaysLeft(notAfter, now time.Time) int {
+	return int(math.Floor(notAfter.Sub(now).Hours() / 24))
+}
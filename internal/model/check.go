@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+)
+
+// CheckResult is the outcome of a single probe against a service.
+// Latency is measured in milliseconds, like Service.ExecutionTime.
+type CheckResult struct {
+	ServiceID    int       `json:"service_id,omitempty"`
+	ServiceName  string    `json:"service_name,omitempty"`
+	CheckedAt    time.Time `json:"checked_at,omitempty"`
+	Success      bool      `json:"success"`
+	StatusCode   int       `json:"status_code,omitempty"`
+	Latency      int64     `json:"latency,omitempty"`
+	ResponseSize int64     `json:"response_size,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	// rolling error rate of the service in percent, including this check
+	ErrorEstimate float64 `json:"error_estimate"`
+	// set when the service presented a TLS certificate
+	Certificate *Certificate `json:"certificate,omitempty"`
+}
+
+// Uptime summarises the availability of a service over a window. MTTR and
+// MTBF are in seconds and are zero when there was no outage.
+type Uptime struct {
+	ServiceName string    `json:"service_name,omitempty"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Checks      int       `json:"checks"`
+	// percentage of the window the service was up, null when no check
+	// covers the window
+	Availability *float64 `json:"availability"`
+	Outages      int      `json:"outages"`
+	MTTR         float64  `json:"mttr_seconds"`
+	MTBF         float64  `json:"mtbf_seconds"`
+}
+
+// LatencyStats describes the latency distribution of the successful checks
+// of a service over a window, all values in milliseconds.
+type LatencyStats struct {
+	ServiceName string          `json:"service_name,omitempty"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Count       int64           `json:"count"`
+	Min         int64           `json:"min"`
+	Max         int64           `json:"max"`
+	Mean        float64         `json:"mean"`
+	P50         float64         `json:"p50"`
+	P90         float64         `json:"p90"`
+	P95         float64         `json:"p95"`
+	P99         float64         `json:"p99"`
+	Histogram   []LatencyBucket `json:"histogram"`
+}
+
+// LatencyBucket counts the checks with From <= latency < To.
+type LatencyBucket struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int64   `json:"count"`
+}
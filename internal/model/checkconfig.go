@@ -0,0 +1,39 @@
+package model
+
+// Check types, Service.Type defaults to CheckHTTP.
+const (
+	CheckHTTP = "http"
+	CheckTCP  = "tcp"
+	CheckDNS  = "dns"
+	CheckTLS  = "tls"
+)
+
+// CheckConfig holds the settings of the non HTTP check types, only the one
+// matching Service.Type is used.
+type CheckConfig struct {
+	TCP *TCPCheckConfig `json:"tcp,omitempty"`
+	DNS *DNSCheckConfig `json:"dns,omitempty"`
+	TLS *TLSCheckConfig `json:"tls,omitempty"`
+}
+
+// TCPCheckConfig connects to Service.Address (host:port), optionally writes
+// Send and expects the reply to contain Expect.
+type TCPCheckConfig struct {
+	Send   string `json:"send,omitempty"`
+	Expect string `json:"expect,omitempty"`
+}
+
+// DNSCheckConfig resolves Service.Address and expects every value of
+// Expected among the records. Server (host:port) overrides the system
+// resolver.
+type DNSCheckConfig struct {
+	RecordType string   `json:"record_type,omitempty"`
+	Expected   []string `json:"expected,omitempty"`
+	Server     string   `json:"server,omitempty"`
+}
+
+// TLSCheckConfig completes a TLS handshake with Service.Address (host:port).
+type TLSCheckConfig struct {
+	ServerName         string `json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+)
+
+type IncidentStatus string
+
+const (
+	IncidentOpen         IncidentStatus = "open"
+	IncidentAcknowledged IncidentStatus = "acknowledged"
+	IncidentResolved     IncidentStatus = "resolved"
+)
+
+type IncidentEventKind string
+
+const (
+	EventOpened       IncidentEventKind = "opened"
+	EventAcknowledged IncidentEventKind = "acknowledged"
+	EventCheckFailed  IncidentEventKind = "check_failed"
+	EventComment      IncidentEventKind = "comment"
+	EventResolved     IncidentEventKind = "resolved"
+)
+
+type Incident struct {
+	ID             int64          `json:"id,omitempty"`
+	ServiceID      int            `json:"service_id,omitempty"`
+	ServiceName    string         `json:"service_name,omitempty"`
+	Status         IncidentStatus `json:"status"`
+	Title          string         `json:"title"`
+	OpenedAt       time.Time      `json:"opened_at"`
+	AcknowledgedAt *time.Time     `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string         `json:"acknowledged_by,omitempty"`
+	ResolvedAt     *time.Time     `json:"resolved_at,omitempty"`
+}
+
+type IncidentEvent struct {
+	ID         int64             `json:"id,omitempty"`
+	IncidentID int64             `json:"incident_id,omitempty"`
+	Kind       IncidentEventKind `json:"kind"`
+	Author     string            `json:"author,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// IncidentTimeline is an incident with its events in chronological order
+// and the error reports filed while it was open.
+type IncidentTimeline struct {
+	Incident     Incident        `json:"incident"`
+	Events       []IncidentEvent `json:"events"`
+	ErrorReports []ErrorReport   `json:"error_reports"`
+}
+
+type IncidentFilter struct {
+	ServiceName string
+	Status      IncidentStatus
+	// only open and acknowledged incidents
+	Unresolved bool
+	// restricts the incidents to these services when not nil
+	ServiceIDs []int
+	Limit      int
+	Offset     int
+}
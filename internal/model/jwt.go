@@ -5,7 +5,7 @@ import (
 )
 
 type JwtCustomClaims struct {
-	Name                 string `json:"name,omitempty"`
-	RoleId               int    `json:"role,omitempty"`
-	jwt.RegisteredClaims `json:"registered_claims,omitempty"`
+	Name   string `json:"name,omitempty"`
+	RoleId int    `json:"role,omitempty"`
+	jwt.RegisteredClaims
 }
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	RecurrenceNone   = ""
+	RecurrenceDaily  = "daily"
+	RecurrenceWeekly = "weekly"
+)
+
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// MaintenanceWindow is planned downtime of one service, or of every service
+// when ServiceID is nil. Recurring windows repeat [StartsAt, EndsAt) every
+// day or week until Until.
+type MaintenanceWindow struct {
+	ID          int        `json:"id,omitempty"`
+	ServiceID   *int       `json:"service_id,omitempty"`
+	ServiceName string     `json:"service_name,omitempty"`
+	Title       string     `json:"title"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	Until       *time.Time `json:"until,omitempty"`
+}
+
+// Occurrences returns the parts of the window that overlap [from, to).
+func (mw MaintenanceWindow) Occurrences(from, to time.Time) (intervals []Interval) {
+	if !mw.EndsAt.After(mw.StartsAt) {
+		return nil
+	}
+
+	days := 0
+	switch mw.Recurrence {
+	case RecurrenceDaily:
+		days = 1
+	case RecurrenceWeekly:
+		days = 7
+	}
+
+	start, end := mw.StartsAt, mw.EndsAt
+	if days > 0 && from.After(end) {
+		// skip the occurrences that ended before from
+		n := int(from.Sub(end).Hours()/24) / days
+		start, end = start.AddDate(0, 0, n*days), end.AddDate(0, 0, n*days)
+	}
+
+	for start.Before(to) {
+		if mw.Until != nil && start.After(*mw.Until) {
+			break
+		}
+		if end.After(from) {
+			i := Interval{Start: start, End: end}
+			if i.Start.Before(from) {
+				i.Start = from
+			}
+			if i.End.After(to) {
+				i.End = to
+			}
+			intervals = append(intervals, i)
+		}
+		if days == 0 {
+			break
+		}
+		start, end = start.AddDate(0, 0, days), end.AddDate(0, 0, days)
+	}
+
+	return intervals
+}
+
+// Active reports whether t falls into the window.
+func (mw MaintenanceWindow) Active(t time.Time) bool {
+	return len(mw.Occurrences(t, t.Add(time.Nanosecond))) > 0
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func at(day, hour int) time.Time {
+	return time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestMaintenanceWindowOccurrences(t *testing.T) {
+	until := at(4, 0)
+
+	tests := []struct {
+		name     string
+		window   MaintenanceWindow
+		from, to time.Time
+		want     []Interval
+	}{
+		{
+			name:   "one off inside",
+			window: MaintenanceWindow{StartsAt: at(2, 1), EndsAt: at(2, 3)},
+			from:   at(1, 0), to: at(3, 0),
+			want: []Interval{{at(2, 1), at(2, 3)}},
+		},
+		{
+			name:   "one off clipped",
+			window: MaintenanceWindow{StartsAt: at(2, 1), EndsAt: at(2, 3)},
+			from:   at(2, 2), to: at(2, 4),
+			want: []Interval{{at(2, 2), at(2, 3)}},
+		},
+		{
+			name:   "one off before",
+			window: MaintenanceWindow{StartsAt: at(1, 1), EndsAt: at(1, 3)},
+			from:   at(2, 0), to: at(3, 0),
+		},
+		{
+			name:   "one off after",
+			window: MaintenanceWindow{StartsAt: at(5, 1), EndsAt: at(5, 3)},
+			from:   at(2, 0), to: at(3, 0),
+		},
+		{
+			name:   "ends when the range starts",
+			window: MaintenanceWindow{StartsAt: at(1, 22), EndsAt: at(2, 0)},
+			from:   at(2, 0), to: at(3, 0),
+		},
+		{
+			name:   "empty window",
+			window: MaintenanceWindow{StartsAt: at(2, 3), EndsAt: at(2, 3)},
+			from:   at(1, 0), to: at(3, 0),
+		},
+		{
+			name:   "daily",
+			window: MaintenanceWindow{StartsAt: at(1, 2), EndsAt: at(1, 3), Recurrence: RecurrenceDaily},
+			from:   at(10, 0), to: at(12, 0),
+			want: []Interval{{at(10, 2), at(10, 3)}, {at(11, 2), at(11, 3)}},
+		},
+		{
+			name:   "daily across midnight",
+			window: MaintenanceWindow{StartsAt: at(1, 23), EndsAt: at(2, 1), Recurrence: RecurrenceDaily},
+			from:   at(10, 0), to: at(11, 0),
+			want: []Interval{{at(10, 0), at(10, 1)}, {at(10, 23), at(11, 0)}},
+		},
+		{
+			name:   "daily until",
+			window: MaintenanceWindow{StartsAt: at(1, 2), EndsAt: at(1, 3), Recurrence: RecurrenceDaily, Until: &until},
+			from:   at(2, 0), to: at(10, 0),
+			want: []Interval{{at(2, 2), at(2, 3)}, {at(3, 2), at(3, 3)}},
+		},
+		{
+			name:   "weekly",
+			window: MaintenanceWindow{StartsAt: at(1, 2), EndsAt: at(1, 4), Recurrence: RecurrenceWeekly},
+			from:   at(2, 0), to: at(16, 0),
+			want: []Interval{{at(8, 2), at(8, 4)}, {at(15, 2), at(15, 4)}},
+		},
+		{
+			name:   "recurring not started yet",
+			window: MaintenanceWindow{StartsAt: at(20, 2), EndsAt: at(20, 4), Recurrence: RecurrenceDaily},
+			from:   at(2, 0), to: at(16, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.window.Occurrences(tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Occurrences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowActive(t *testing.T) {
+	window := MaintenanceWindow{StartsAt: at(1, 2), EndsAt: at(1, 3), Recurrence: RecurrenceDaily}
+
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{t: at(1, 1), want: false},
+		{t: at(1, 2), want: true},
+		{t: at(1, 2).Add(59 * time.Minute), want: true},
+		{t: at(1, 3), want: false},
+		{t: at(20, 2).Add(30 * time.Minute), want: true},
+		{t: at(20, 4), want: false},
+	}
+	for _, tt := range tests {
+		if got := window.Active(tt.t); got != tt.want {
+			t.Errorf("Active(%s) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package model
+
+import "context"
+
+type Permission string
+
+const (
+	// read the services assigned to the caller, their checks and incidents
+	PermServiceRead Permission = "service:read"
+	// read every service and its incidents, assigned or not
+	PermServiceReadAll Permission = "service:read_all"
+	// add, update and delete services, their alert rules and maintenance
+	PermServiceWrite Permission = "service:write"
+	// acknowledge and comment incidents of assigned services
+	PermIncidentWrite Permission = "incident:write"
+	// read views spanning every service, e.g. the system snapshot
+	PermSystemRead Permission = "system:read"
+	// manage users and their service assignments
+	PermUserAdmin Permission = "user:admin"
+)
+
+// RolePermissions are the permissions granted to each access level.
+var RolePermissions = map[AccessLevel][]Permission{
+	Admin:   {PermServiceRead, PermServiceReadAll, PermServiceWrite, PermIncidentWrite, PermSystemRead, PermUserAdmin},
+	Regular: {PermServiceRead, PermIncidentWrite},
+	Demo:    {PermServiceRead},
+}
+
+// Valid reports whether p is granted to any role.
+func (p Permission) Valid() bool {
+	for _, perm := range RolePermissions[Admin] {
+		if perm == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserID      int          `json:"user_id"`
+	Name        string       `json:"name"`
+	Role        AccessLevel  `json:"role"`
+	Permissions []Permission `json:"permissions"`
+	// set when the caller authenticated with an API key
+	APIKeyID int `json:"api_key_id,omitempty"`
+}
+
+// NewPrincipal returns a principal with the permissions of its role.
+func NewPrincipal(userID int, name string, role AccessLevel) *Principal {
+	return &Principal{
+		UserID:      userID,
+		Name:        name,
+		Role:        role,
+		Permissions: RolePermissions[role],
+	}
+}
+
+func (p *Principal) Can(perm Permission) bool {
+	for _, granted := range p.Permissions {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the caller, so the
+// usecases can tell who made a change.
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the caller stored in ctx, nil when there's
+// none, e.g. for changes made by the system.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
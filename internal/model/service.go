@@ -9,19 +9,36 @@ type Service struct {
 	Name          *string                `json:"name,omitempty"`
 	Address       *string                `json:"address,omitempty"`
 	Method        *string                `json:"method,omitempty"`
+	Type          *string                `json:"type,omitempty"`
+	Config        *CheckConfig           `json:"config,omitempty"`
 	Header        map[string]string      `json:"header,omitempty"`
 	Body          map[string]interface{} `json:"body,omitempty"`
 	AccessLevel   AccessLevel            `json:"access_level,omitempty"`
 	ExecutionTime *int64                 `json:"execution_time,omitempty"`
-	ErrorEstimate int
+	Interval      *string                `json:"interval,omitempty"`
+	Assertions    []Assertion            `json:"assertions,omitempty"`
+	Public        *bool                  `json:"public,omitempty"`
+	ErrorEstimate float64                `json:"error_estimate"`
 }
 
 type ErrorReport struct {
+	ID          int64     `json:"id,omitempty"`
+	ServiceID   int       `json:"service_id,omitempty"`
 	ServiceName string    `json:"service_name,omitempty"`
 	Log         string    `json:"log,omitempty"`
 	OccurredAt  time.Time `json:"occurred_at,omitempty"`
 }
 
+type ErrorReportFilter struct {
+	ServiceName string
+	// restricts the reports to these services when not nil
+	ServiceNames []string
+	From         time.Time
+	To           time.Time
+	Limit        int
+	Offset       int
+}
+
 type System struct {
 	Services     []Service     `json:"services,omitempty"`
 	Users        []User        `json:"users,omitempty"`
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+// StatusPage is the public view of the services opted in to it.
+type StatusPage struct {
+	Title       string          `json:"title"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Services    []ServiceStatus `json:"services"`
+	Incidents   []Incident      `json:"incidents"`
+}
+
+type ServiceStatus struct {
+	Name          string        `json:"name"`
+	Up            bool          `json:"up"`
+	LastCheckedAt *time.Time    `json:"last_checked_at,omitempty"`
+	Uptime        float64       `json:"uptime"`
+	Days          []DailyUptime `json:"days"`
+}
+
+// DailyUptime is the availability of one day in percent, computed like
+// Uptime.Availability, Checks is zero for days without data.
+type DailyUptime struct {
+	Date         time.Time `json:"date"`
+	Checks       int64     `json:"checks"`
+	Availability float64   `json:"availability"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// Team owns services, its members can access every one of them.
+type Team struct {
+	ID          int       `json:"id,omitempty"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UserIDs     []int     `json:"user_ids"`
+	ServiceIDs  []int     `json:"service_ids"`
+}
+
+type TeamMember struct {
+	TeamID int `json:"team_id"`
+	UserID int `json:"user_id"`
+}
+
+type TeamService struct {
+	TeamID    int `json:"team_id"`
+	ServiceID int `json:"service_id"`
+}
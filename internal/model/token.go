@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// TokenPair is returned on login and refresh.
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	// lifetime of the access token in seconds
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+type RefreshToken struct {
+	ID        int64      `json:"id"`
+	UserID    int        `json:"user_id"`
+	Username  string     `json:"username"`
+	IssuedAt  time.Time  `json:"issued_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,232 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"monitoring/internal/model"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidateAssertions reports the first assertion that can never be
+// evaluated, e.g. an unknown type or a regular expression that doesn't
+// compile.
+func ValidateAssertions(assertions []model.Assertion) error {
+	for i, a := range assertions {
+		var err error
+		switch a.Type {
+		case model.AssertStatus:
+			if len(a.Codes) == 0 {
+				err = fmt.Errorf("codes must be filled")
+			}
+		case model.AssertBodyContains:
+			if _, ok := a.Value.(string); !ok {
+				err = fmt.Errorf("value must be a string")
+			}
+		case model.AssertBodyRegex:
+			pattern, ok := a.Value.(string)
+			if !ok {
+				err = fmt.Errorf("value must be a string")
+			} else {
+				_, err = regexp.Compile(pattern)
+			}
+		case model.AssertJSONPathEquals, model.AssertJSONPathExists:
+			_, err = parseJSONPath(a.Path)
+		case model.AssertHeader:
+			if a.Name == "" {
+				err = fmt.Errorf("name must be filled")
+			}
+		case model.AssertMaxLatency:
+			if a.Max <= 0 {
+				err = fmt.Errorf("max must be positive")
+			}
+		default:
+			err = fmt.Errorf("unknown type")
+		}
+		if err != nil {
+			return fmt.Errorf("assertion %d (%s): %w", i, a.Type, err)
+		}
+	}
+	return nil
+}
+
+// checkAssertions returns an error describing the first failing assertion.
+// Without a status assertion any status below 400 is accepted.
+func checkAssertions(assertions []model.Assertion, resp *http.Response, payload []byte, latency int64) error {
+	hasStatus := false
+	for _, a := range assertions {
+		if a.Type == model.AssertStatus {
+			hasStatus = true
+		}
+		if err := checkAssertion(a, resp, payload, latency); err != nil {
+			return fmt.Errorf("assertion %s failed: %w", a.Type, err)
+		}
+	}
+
+	if !hasStatus && resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func checkAssertion(a model.Assertion, resp *http.Response, payload []byte, latency int64) error {
+	switch a.Type {
+	case model.AssertStatus:
+		for _, code := range a.Codes {
+			if resp.StatusCode == code {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected status in %v, got %d", a.Codes, resp.StatusCode)
+
+	case model.AssertBodyContains:
+		s, _ := a.Value.(string)
+		if !strings.Contains(string(payload), s) {
+			return fmt.Errorf("body doesn't contain %q", s)
+		}
+
+	case model.AssertBodyRegex:
+		s, _ := a.Value.(string)
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return err
+		}
+		if !re.Match(payload) {
+			return fmt.Errorf("body doesn't match %q", s)
+		}
+
+	case model.AssertJSONPathEquals, model.AssertJSONPathExists:
+		var doc interface{}
+		if err := json.Unmarshal(payload, &doc); err != nil {
+			return fmt.Errorf("body isn't JSON: %w", err)
+		}
+		v, ok, err := lookupJSONPath(doc, a.Path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s doesn't exist", a.Path)
+		}
+		if a.Type == model.AssertJSONPathEquals && !jsonEqual(v, a.Value) {
+			return fmt.Errorf("%s is %v, expected %v", a.Path, v, a.Value)
+		}
+
+	case model.AssertHeader:
+		values, ok := resp.Header[http.CanonicalHeaderKey(a.Name)]
+		if !ok {
+			return fmt.Errorf("header %s is missing", a.Name)
+		}
+		if a.Value == nil {
+			return nil
+		}
+		expected := fmt.Sprint(a.Value)
+		for _, v := range values {
+			if v == expected {
+				return nil
+			}
+		}
+		return fmt.Errorf("header %s is %q, expected %q", a.Name, strings.Join(values, ", "), expected)
+
+	case model.AssertMaxLatency:
+		if latency > a.Max {
+			return fmt.Errorf("took %dms, expected at most %dms", latency, a.Max)
+		}
+
+	default:
+		return fmt.Errorf("unknown type")
+	}
+
+	return nil
+}
+
+// jsonEqual compares values the way they'd compare after a JSON round trip,
+// so 1 and 1.0 are equal.
+func jsonEqual(a, b interface{}) bool {
+	normalize := func(v interface{}) interface{} {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var out interface{}
+		if err := json.Unmarshal(raw, &out); err != nil {
+			return v
+		}
+		return out
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// parseJSONPath splits a path like $.items[0]["a.b"].id into its keys and
+// indexes. Only the child and index operators are supported.
+func parseJSONPath(path string) (segments []interface{}, err error) {
+	p := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+			segments = append(segments, p[:end])
+			p = p[end:]
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+			inner := p[1:end]
+			p = p[end+1:]
+			if unquoted, err := strconv.Unquote(inner); err == nil {
+				segments = append(segments, unquoted)
+			} else if strings.HasPrefix(inner, "'") && strings.HasSuffix(inner, "'") && len(inner) >= 2 {
+				segments = append(segments, inner[1:len(inner)-1])
+			} else if i, err := strconv.Atoi(inner); err == nil {
+				segments = append(segments, i)
+			} else {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+		default:
+			if len(segments) > 0 {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+			// allow "a.b" as a shorthand of "$.a.b"
+			p = "." + p
+		}
+	}
+	return segments, nil
+}
+
+func lookupJSONPath(doc interface{}, path string) (interface{}, bool, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cur := doc
+	for _, seg := range segments {
+		switch key := seg.(type) {
+		case string:
+			obj, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil, false, nil
+			}
+			if cur, ok = obj[key]; !ok {
+				return nil, false, nil
+			}
+		case int:
+			arr, ok := cur.([]interface{})
+			if !ok || key < 0 || key >= len(arr) {
+				return nil, false, nil
+			}
+			cur = arr[key]
+		}
+	}
+	return cur, true, nil
+}
@@ -0,0 +1,283 @@
+package probe
+
+import (
+	"monitoring/internal/model"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []interface{}
+		err  bool
+	}{
+		{path: "$", want: nil},
+		{path: "$.status", want: []interface{}{"status"}},
+		{path: "status.code", want: []interface{}{"status", "code"}},
+		{path: "$.items[0].id", want: []interface{}{"items", 0, "id"}},
+		{path: "$[2][10]", want: []interface{}{2, 10}},
+		{path: `$["a.b"].c`, want: []interface{}{"a.b", "c"}},
+		{path: "$['a b']", want: []interface{}{"a b"}},
+		{path: " $.a ", want: []interface{}{"a"}},
+		{path: "$..a", err: true},
+		{path: "$.a.", err: true},
+		{path: "$.a[", err: true},
+		{path: "$.a[x]", err: true},
+		{path: "$.a[*]", err: true},
+		{path: "$a", want: []interface{}{"a"}},
+		{path: "$.a[0]b", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseJSONPath(tt.path)
+		if tt.err {
+			if err == nil {
+				t.Errorf("parseJSONPath(%q) = %v, expected an error", tt.path, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseJSONPath(%q): %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseJSONPath(%q) = %#v, want %#v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestJSONEqual(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		want bool
+	}{
+		{a: 1, b: 1.0, want: true},
+		{a: float64(200), b: 200, want: true},
+		{a: 1, b: "1", want: false},
+		{a: "ok", b: "ok", want: true},
+		{a: true, b: "true", want: false},
+		{a: nil, b: nil, want: true},
+		{a: nil, b: 0, want: false},
+		{a: []interface{}{1.0, "a"}, b: []int{1}, want: false},
+		{a: map[string]interface{}{"a": 1.0}, b: map[string]int{"a": 1}, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := jsonEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("jsonEqual(%#v, %#v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAssertions(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+			"X-Version":    {"2"},
+		},
+	}
+	payload := []byte(`{"status": "ok", "count": 3, "code": "3", "items": [{"id": 7}], "a.b": null}`)
+
+	tests := []struct {
+		name       string
+		assertions []model.Assertion
+		resp       *http.Response
+		payload    []byte
+		latency    int64
+		err        string
+	}{
+		{name: "none"},
+		{
+			name: "status accepted without assertion",
+			resp: &http.Response{StatusCode: http.StatusFound},
+		},
+		{
+			name: "error status without assertion",
+			resp: &http.Response{StatusCode: http.StatusServiceUnavailable},
+			err:  "unexpected status code 503",
+		},
+		{
+			name:       "status assertion allows errors",
+			assertions: []model.Assertion{{Type: model.AssertStatus, Codes: []int{503}}},
+			resp:       &http.Response{StatusCode: http.StatusServiceUnavailable},
+		},
+		{
+			name:       "status mismatch",
+			assertions: []model.Assertion{{Type: model.AssertStatus, Codes: []int{201, 204}}},
+			err:        "expected status in [201 204], got 200",
+		},
+		{
+			name:       "body contains",
+			assertions: []model.Assertion{{Type: model.AssertBodyContains, Value: `"ok"`}},
+		},
+		{
+			name:       "body doesn't contain",
+			assertions: []model.Assertion{{Type: model.AssertBodyContains, Value: "error"}},
+			err:        "body doesn't contain",
+		},
+		{
+			name:       "body regex",
+			assertions: []model.Assertion{{Type: model.AssertBodyRegex, Value: `"count":\s*\d+`}},
+		},
+		{
+			name:       "body regex mismatch",
+			assertions: []model.Assertion{{Type: model.AssertBodyRegex, Value: `^\[`}},
+			err:        "body doesn't match",
+		},
+		{
+			name:       "json string",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathEquals, Path: "$.status", Value: "ok"}},
+		},
+		{
+			name:       "json number",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathEquals, Path: "$.count", Value: 3}},
+		},
+		{
+			name:       "json number isn't a string",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathEquals, Path: "$.count", Value: "3"}},
+			err:        "$.count is 3, expected 3",
+		},
+		{
+			name:       "json string isn't a number",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathEquals, Path: "$.code", Value: 3}},
+			err:        "$.code is 3, expected 3",
+		},
+		{
+			name:       "json array index",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathEquals, Path: "$.items[0].id", Value: 7}},
+		},
+		{
+			name:       "json index out of range",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathExists, Path: "$.items[1]"}},
+			err:        "$.items[1] doesn't exist",
+		},
+		{
+			name:       "json index into object",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathExists, Path: "$.status[0]"}},
+			err:        "doesn't exist",
+		},
+		{
+			name:       "json missing key",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathExists, Path: "$.missing"}},
+			err:        "$.missing doesn't exist",
+		},
+		{
+			name:       "json null exists",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathExists, Path: `$["a.b"]`}},
+		},
+		{
+			name:       "json bad path",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathExists, Path: "$..status"}},
+			err:        "invalid path",
+		},
+		{
+			name:       "json body isn't json",
+			assertions: []model.Assertion{{Type: model.AssertJSONPathExists, Path: "$.status"}},
+			payload:    []byte("<html>"),
+			err:        "body isn't JSON",
+		},
+		{
+			name:       "header present",
+			assertions: []model.Assertion{{Type: model.AssertHeader, Name: "content-type"}},
+		},
+		{
+			name:       "header value",
+			assertions: []model.Assertion{{Type: model.AssertHeader, Name: "Content-Type", Value: "application/json"}},
+		},
+		{
+			name:       "header number value",
+			assertions: []model.Assertion{{Type: model.AssertHeader, Name: "X-Version", Value: float64(2)}},
+		},
+		{
+			name:       "header missing",
+			assertions: []model.Assertion{{Type: model.AssertHeader, Name: "X-Missing"}},
+			err:        "header X-Missing is missing",
+		},
+		{
+			name:       "header mismatch",
+			assertions: []model.Assertion{{Type: model.AssertHeader, Name: "X-Version", Value: "3"}},
+			err:        `header X-Version is "2", expected "3"`,
+		},
+		{
+			name:       "latency",
+			assertions: []model.Assertion{{Type: model.AssertMaxLatency, Max: 100}},
+			latency:    100,
+		},
+		{
+			name:       "too slow",
+			assertions: []model.Assertion{{Type: model.AssertMaxLatency, Max: 100}},
+			latency:    101,
+			err:        "took 101ms, expected at most 100ms",
+		},
+		{
+			name: "first failure is reported",
+			assertions: []model.Assertion{
+				{Type: model.AssertStatus, Codes: []int{200}},
+				{Type: model.AssertBodyContains, Value: "missing"},
+				{Type: model.AssertMaxLatency, Max: 1},
+			},
+			latency: 5,
+			err:     "assertion body_contains failed",
+		},
+		{
+			name:       "unknown type",
+			assertions: []model.Assertion{{Type: "bogus"}},
+			err:        "unknown type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, p := tt.resp, tt.payload
+			if r == nil {
+				r = resp
+			}
+			if p == nil {
+				p = payload
+			}
+			err := checkAssertions(tt.assertions, r, p, tt.latency)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("got error %v, expected one containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateAssertions(t *testing.T) {
+	valid := []model.Assertion{
+		{Type: model.AssertStatus, Codes: []int{200}},
+		{Type: model.AssertBodyContains, Value: "ok"},
+		{Type: model.AssertBodyRegex, Value: "^ok$"},
+		{Type: model.AssertJSONPathEquals, Path: "$.a[0]", Value: 1},
+		{Type: model.AssertJSONPathExists, Path: "a.b"},
+		{Type: model.AssertHeader, Name: "X-A"},
+		{Type: model.AssertMaxLatency, Max: 10},
+	}
+	if err := ValidateAssertions(valid); err != nil {
+		t.Fatalf("valid assertions: %v", err)
+	}
+
+	for _, a := range []model.Assertion{
+		{Type: model.AssertStatus},
+		{Type: model.AssertBodyContains, Value: 1},
+		{Type: model.AssertBodyRegex, Value: "("},
+		{Type: model.AssertJSONPathEquals, Path: "$..a"},
+		{Type: model.AssertHeader},
+		{Type: model.AssertMaxLatency},
+		{Type: "bogus"},
+	} {
+		if err := ValidateAssertions([]model.Assertion{a}); err == nil {
+			t.Errorf("%+v passed validation", a)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package probe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"monitoring/internal/model"
+	"time"
+)
+
+// certificateInfo summarises the chain of a TLS connection to host.
+func certificateInfo(state *tls.ConnectionState, host string) *model.Certificate {
+	if state == nil {
+		return nil
+	}
+	return chainInfo(state.PeerCertificates, host)
+}
+
+// rejectedCertificateInfo summarises the chain a handshake failed to
+// verify, so expired and mismatched certificates are recorded as well.
+func rejectedCertificateInfo(err error, host string) *model.Certificate {
+	var verifyErr *tls.CertificateVerificationError
+	if !errors.As(err, &verifyErr) {
+		return nil
+	}
+	return chainInfo(verifyErr.UnverifiedCertificates, host)
+}
+
+func chainInfo(chain []*x509.Certificate, host string) *model.Certificate {
+	if len(chain) == 0 {
+		return nil
+	}
+
+	leaf := chain[0]
+	cert := &model.Certificate{
+		Subject:       leaf.Subject.String(),
+		Issuer:        leaf.Issuer.String(),
+		SANs:          append([]string{}, leaf.DNSNames...),
+		NotBefore:     leaf.NotBefore,
+		NotAfter:      leaf.NotAfter,
+		HostnameMatch: leaf.VerifyHostname(host) == nil,
+	}
+	for _, ip := range leaf.IPAddresses {
+		cert.SANs = append(cert.SANs, ip.String())
+	}
+	for _, c := range chain[1:] {
+		if c.NotAfter.Before(cert.NotAfter) {
+			cert.NotAfter = c.NotAfter
+		}
+	}
+	cert.DaysLeft = model.CertificateDaysLeft(cert.NotAfter, time.Now())
+
+	return cert
+}
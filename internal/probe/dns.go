@@ -0,0 +1,106 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"monitoring/internal/model"
+	"net"
+	"strings"
+	"time"
+)
+
+// DNSProber resolves the service address and compares the records with the
+// expected ones.
+type DNSProber struct{}
+
+func (dp *DNSProber) Probe(ctx context.Context, service model.Service) (result model.CheckResult) {
+	if service.Address == nil || *service.Address == "" {
+		result.Error = "service has no address"
+		return
+	}
+	var cfg model.DNSCheckConfig
+	if service.Config != nil && service.Config.DNS != nil {
+		cfg = *service.Config.DNS
+	}
+
+	resolver := net.DefaultResolver
+	if cfg.Server != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, cfg.Server)
+			},
+		}
+	}
+
+	start := time.Now()
+	records, err := lookup(ctx, resolver, cfg.RecordType, *service.Address)
+	result.Latency = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	if len(records) == 0 {
+		result.Error = "no records found"
+		return
+	}
+
+	found := make(map[string]bool, len(records))
+	for _, r := range records {
+		found[normalizeRecord(r)] = true
+	}
+	for _, expected := range cfg.Expected {
+		if !found[normalizeRecord(expected)] {
+			result.Error = fmt.Sprintf("expected record %q, got %v", expected, records)
+			return
+		}
+	}
+
+	result.Success = true
+	return
+}
+
+func lookup(ctx context.Context, resolver *net.Resolver, recordType, host string) (records []string, err error) {
+	switch strings.ToUpper(recordType) {
+	case "", "A", "AAAA":
+		network := "ip"
+		switch strings.ToUpper(recordType) {
+		case "A":
+			network = "ip4"
+		case "AAAA":
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, host)
+		for _, ip := range ips {
+			records = append(records, ip.String())
+		}
+		return records, err
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		return []string{cname}, nil
+	case "MX":
+		mxs, err := resolver.LookupMX(ctx, host)
+		for _, mx := range mxs {
+			records = append(records, mx.Host)
+		}
+		return records, err
+	case "NS":
+		nss, err := resolver.LookupNS(ctx, host)
+		for _, ns := range nss {
+			records = append(records, ns.Host)
+		}
+		return records, err
+	case "TXT":
+		return resolver.LookupTXT(ctx, host)
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+}
+
+func normalizeRecord(r string) string {
+	return strings.TrimSuffix(strings.ToLower(r), ".")
+}
@@ -0,0 +1,138 @@
+package probe
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsServer answers the queries of zone over UDP, names it doesn't know get
+// NXDOMAIN. It returns the server's address.
+func dnsServer(t *testing.T, zone map[string][]dnsmessage.Resource) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) == 0 {
+				continue
+			}
+			question := query.Questions[0]
+
+			reply := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: true, RCode: dnsmessage.RCodeSuccess},
+				Questions: query.Questions,
+			}
+			records, known := zone[strings.ToLower(question.Name.String())]
+			if !known {
+				reply.RCode = dnsmessage.RCodeNameError
+			}
+			for _, r := range records {
+				if r.Header.Type == question.Type {
+					r.Header.Name = question.Name
+					r.Header.Class = dnsmessage.ClassINET
+					r.Header.TTL = 60
+					reply.Answers = append(reply.Answers, r)
+				}
+			}
+			packed, err := reply.Pack()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(packed, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestDNSProber(t *testing.T) {
+	server := dnsServer(t, map[string][]dnsmessage.Resource{
+		"svc.test.": {
+			{Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeA}, Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+			{Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeA}, Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}}},
+		},
+		"txt.test.": {
+			{Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeTXT}, Body: &dnsmessage.TXTResource{TXT: []string{"v=spf1 -all"}}},
+		},
+	})
+
+	dnsService := func(host string, cfg model.DNSCheckConfig) model.Service {
+		cfg.Server = server
+		return model.Service{Address: &host, Config: &model.CheckConfig{DNS: &cfg}}
+	}
+
+	tests := []struct {
+		name    string
+		service model.Service
+		err     string
+	}{
+		{
+			name:    "a records",
+			service: dnsService("svc.test", model.DNSCheckConfig{RecordType: "A", Expected: []string{"10.0.0.2"}}),
+		},
+		{
+			name:    "any record",
+			service: dnsService("svc.test", model.DNSCheckConfig{RecordType: "A"}),
+		},
+		{
+			name:    "missing expected record",
+			service: dnsService("svc.test", model.DNSCheckConfig{RecordType: "A", Expected: []string{"10.0.0.3"}}),
+			err:     `expected record "10.0.0.3"`,
+		},
+		{
+			name:    "txt record",
+			service: dnsService("txt.test", model.DNSCheckConfig{RecordType: "txt", Expected: []string{"v=spf1 -all"}}),
+		},
+		{
+			name:    "unknown name",
+			service: dnsService("missing.test", model.DNSCheckConfig{RecordType: "A"}),
+			err:     "no such host",
+		},
+		{
+			name:    "unsupported type",
+			service: dnsService("svc.test", model.DNSCheckConfig{RecordType: "SRV"}),
+			err:     `unsupported record type "SRV"`,
+		},
+		{
+			name:    "no address",
+			service: model.Service{},
+			err:     "service has no address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			result := (&DNSProber{}).Probe(ctx, tt.service)
+			if result.ResponseSize != 0 {
+				t.Errorf("response size = %d, dns checks have no body", result.ResponseSize)
+			}
+			if tt.err == "" {
+				if !result.Success {
+					t.Fatalf("probe failed: %s", result.Error)
+				}
+				return
+			}
+			if result.Success || !strings.Contains(result.Error, tt.err) {
+				t.Fatalf("got success %v, error %q, expected %q", result.Success, result.Error, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"monitoring/internal/alert"
+	. "monitoring/internal/globals"
+	"monitoring/internal/metrics"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/stream"
+	"monitoring/internal/util/midlog"
+	"time"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+// DefaultCertWarnDays are the days before expiry at which a certificate
+// warning is reported.
+var DefaultCertWarnDays = []int{30, 14, 7, 1}
+
+var logger = midlog.LoggerForModule("probe")
+
+// Recorder persists or reacts to the result of a check.
+type Recorder interface {
+	Record(ctx context.Context, service model.Service, result model.CheckResult) error
+}
+
+// RecorderFunc adapts an ordinary function to the Recorder interface.
+type RecorderFunc func(ctx context.Context, service model.Service, result model.CheckResult) error
+
+func (f RecorderFunc) Record(ctx context.Context, service model.Service, result model.CheckResult) error {
+	return f(ctx, service, result)
+}
+
+// Forgetter is implemented by recorders that keep state per service, it's
+// dropped once the service is removed.
+type Forgetter interface {
+	Forget(service model.Service)
+}
+
+// Engine probes the registered services, updates their error estimate and
+// hands every result to its recorders in order.
+type Engine struct {
+	IServicesRepo repository.IServicesRepository
+	Probers       map[string]Prober
+	Estimator     *ErrorEstimator
+	Recorders     []Recorder
+	Timeout       time.Duration
+}
+
+func NewEngine() *Engine {
+	servicesRepo := &repository.ServicesRepository{DB: GlobalPG}
+	errorReportsRepo := &repository.ErrorReportsRepository{DB: GlobalPG}
+
+	warnDays := GlobalConfig.Probe.CertWarnDays
+	if len(warnDays) == 0 {
+		warnDays = DefaultCertWarnDays
+	}
+
+	return &Engine{
+		IServicesRepo: servicesRepo,
+		Probers:       DefaultProbers(),
+		Estimator:     NewErrorEstimator(servicesRepo, GlobalConfig.Probe.ErrorWindow),
+		Recorders: []Recorder{
+			&ServiceRecorder{IServicesRepo: servicesRepo},
+			&CheckRecorder{IServiceChecksRepo: &repository.ServiceChecksRepository{DB: GlobalPG}},
+			&ErrorReportRecorder{IErrorReportsRepo: errorReportsRepo},
+			&CertificateRecorder{
+				ICertificatesRepo: &repository.CertificatesRepository{DB: GlobalPG},
+				IErrorReportsRepo: errorReportsRepo,
+				WarnDays:          warnDays,
+			},
+			&IncidentRecorder{IIncidentsRepo: &repository.IncidentsRepository{DB: GlobalPG}},
+			metrics.NewProbeRecorder(),
+			stream.Default,
+			alert.NewManager(),
+			RecorderFunc(logResult),
+		},
+		Timeout: DefaultTimeout,
+	}
+}
+
+// DefaultProbers returns a prober for every supported check type.
+func DefaultProbers() map[string]Prober {
+	return map[string]Prober{
+		model.CheckHTTP: &HTTPProber{},
+		model.CheckTCP:  &TCPProber{},
+		model.CheckDNS:  &DNSProber{},
+		model.CheckTLS:  &TLSProber{},
+	}
+}
+
+// Forget drops the state the recorders keep for a removed service.
+func (e *Engine) Forget(service model.Service) {
+	for _, recorder := range e.Recorders {
+		if f, ok := recorder.(Forgetter); ok {
+			f.Forget(service)
+		}
+	}
+}
+
+// Check probes a single service and records the result.
+func (e *Engine) Check(ctx context.Context, service model.Service) model.CheckResult {
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	checkedAt := time.Now()
+	var result model.CheckResult
+	if prober, ok := e.Probers[checkType(service)]; ok {
+		result = prober.Probe(probeCtx, service)
+	} else {
+		result.Error = fmt.Sprintf("unsupported check type %q", checkType(service))
+	}
+	result.ServiceID = service.ID
+	if service.Name != nil {
+		result.ServiceName = *service.Name
+	}
+	result.CheckedAt = checkedAt
+
+	if e.Estimator != nil {
+		estimate, err := e.Estimator.Update(ctx, service.ID, result.Success)
+		if err != nil {
+			logger.ErrorEF(err, "Error updating error estimate of service %q", result.ServiceName)
+		}
+		result.ErrorEstimate = estimate
+	}
+
+	for _, recorder := range e.Recorders {
+		if err := recorder.Record(ctx, service, result); err != nil {
+			logger.ErrorEF(err, "Error recording check of service %q", result.ServiceName)
+		}
+	}
+
+	return result
+}
+
+func checkType(service model.Service) string {
+	if service.Type == nil || *service.Type == "" {
+		return model.CheckHTTP
+	}
+	return *service.Type
+}
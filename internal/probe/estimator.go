@@ -0,0 +1,38 @@
+package probe
+
+import (
+	"context"
+	"monitoring/internal/repository"
+)
+
+// DefaultErrorWindow is the number of checks the error estimate roughly
+// averages over.
+const DefaultErrorWindow = 20
+
+// ErrorEstimator keeps Service.ErrorEstimate as an exponentially weighted
+// moving average of the failure rate in percent. An EWMA with
+// alpha = 2/(N+1) has the same center of mass as a simple average over the
+// last N checks.
+type ErrorEstimator struct {
+	IServicesRepo repository.IServicesRepository
+	Alpha         float64
+}
+
+func NewErrorEstimator(servicesRepo repository.IServicesRepository, window int) *ErrorEstimator {
+	if window <= 0 {
+		window = DefaultErrorWindow
+	}
+	return &ErrorEstimator{
+		IServicesRepo: servicesRepo,
+		Alpha:         2 / float64(window+1),
+	}
+}
+
+// Update records the outcome of a check and returns the new estimate.
+func (ee *ErrorEstimator) Update(ctx context.Context, serviceID int, success bool) (float64, error) {
+	var sample float64
+	if !success {
+		sample = 100
+	}
+	return ee.IServicesRepo.UpdateErrorEstimate(ctx, serviceID, sample, ee.Alpha)
+}
@@ -0,0 +1,126 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"monitoring/internal/model"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MaxBodySize caps how much of a response body is read by a probe.
+const MaxBodySize = 1 << 20
+
+// Prober executes a single check against a service.
+type Prober interface {
+	Probe(ctx context.Context, service model.Service) model.CheckResult
+}
+
+// ValidateCheck reports whether checkType is supported and config has the
+// settings it needs.
+func ValidateCheck(checkType string, config *model.CheckConfig) error {
+	switch checkType {
+	case "", model.CheckHTTP, model.CheckTCP, model.CheckTLS:
+		return nil
+	case model.CheckDNS:
+		if config != nil && config.DNS != nil {
+			switch strings.ToUpper(config.DNS.RecordType) {
+			case "", "A", "AAAA", "CNAME", "MX", "NS", "TXT":
+			default:
+				return fmt.Errorf("unsupported record type %q", config.DNS.RecordType)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported check type %q", checkType)
+	}
+}
+
+// HTTPProber sends the request configured on a service (method, header and
+// body) and reports the response status, size, latency and for https the
+// certificate chain. The check fails
+// on the first failing assertion of the service.
+type HTTPProber struct {
+	Client *http.Client
+}
+
+func (hp *HTTPProber) Probe(ctx context.Context, service model.Service) (result model.CheckResult) {
+	req, err := newRequest(ctx, service)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	start := time.Now()
+	resp, err := hp.client().Do(req)
+	if err != nil {
+		result.Latency = time.Since(start).Milliseconds()
+		result.Error = err.Error()
+		result.Certificate = rejectedCertificateInfo(err, req.URL.Hostname())
+		return
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
+	result.Latency = time.Since(start).Milliseconds()
+	result.StatusCode = resp.StatusCode
+	result.ResponseSize = int64(len(payload))
+	result.Certificate = certificateInfo(resp.TLS, req.URL.Hostname())
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	if err := checkAssertions(service.Assertions, resp, payload, result.Latency); err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	result.Success = true
+	return
+}
+
+func (hp *HTTPProber) client() *http.Client {
+	if hp.Client == nil {
+		return http.DefaultClient
+	}
+	return hp.Client
+}
+
+func newRequest(ctx context.Context, service model.Service) (*http.Request, error) {
+	if service.Address == nil || *service.Address == "" {
+		return nil, errors.New("service has no address")
+	}
+
+	method := http.MethodGet
+	if service.Method != nil && *service.Method != "" {
+		method = strings.ToUpper(*service.Method)
+	}
+
+	var body io.Reader
+	if len(service.Body) > 0 {
+		b, err := json.Marshal(service.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, *service.Address, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range service.Header {
+		req.Header.Set(k, v)
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
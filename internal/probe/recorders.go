@@ -0,0 +1,190 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/util/midlog"
+	"time"
+)
+
+// ServiceRecorder stores the latest measured latency on the service row.
+type ServiceRecorder struct {
+	IServicesRepo repository.IServicesRepository
+}
+
+func (sr *ServiceRecorder) Record(ctx context.Context, service model.Service, result model.CheckResult) error {
+	return sr.IServicesRepo.SetExecutionTime(ctx, service.ID, result.Latency)
+}
+
+// CheckRecorder appends every result to the service_checks history.
+type CheckRecorder struct {
+	IServiceChecksRepo repository.IServiceChecksRepository
+}
+
+func (cr *CheckRecorder) Record(ctx context.Context, service model.Service, result model.CheckResult) error {
+	return cr.IServiceChecksRepo.Add(ctx, result)
+}
+
+// ErrorReportRecorder files an error report for every failed check.
+type ErrorReportRecorder struct {
+	IErrorReportsRepo repository.IErrorReportsRepository
+}
+
+func (er *ErrorReportRecorder) Record(ctx context.Context, service model.Service, result model.CheckResult) error {
+	if result.Success {
+		return nil
+	}
+	return er.IErrorReportsRepo.Add(ctx, model.ErrorReport{
+		ServiceID:   result.ServiceID,
+		ServiceName: result.ServiceName,
+		Log:         result.Error,
+		OccurredAt:  result.CheckedAt,
+	})
+}
+
+// CertificateRecorder stores the certificate seen by a check and files an
+// error report when its expiry crosses one of WarnDays, or when it stops
+// matching the hostname.
+type CertificateRecorder struct {
+	ICertificatesRepo repository.ICertificatesRepository
+	IErrorReportsRepo repository.IErrorReportsRepository
+	WarnDays          []int
+}
+
+func (cr *CertificateRecorder) Record(ctx context.Context, service model.Service, result model.CheckResult) error {
+	if result.Certificate == nil {
+		return nil
+	}
+	cert := *result.Certificate
+	cert.ServiceID = result.ServiceID
+	cert.ServiceName = result.ServiceName
+	cert.CheckedAt = result.CheckedAt
+
+	prev, err := cr.ICertificatesRepo.Get(ctx, cert.ServiceID)
+	if err != nil {
+		return err
+	}
+	if err := cr.ICertificatesRepo.Upsert(ctx, cert); err != nil {
+		return err
+	}
+
+	var logs []string
+	threshold := cr.threshold(cert.DaysLeft)
+	if threshold >= 0 && (prev.ServiceID == 0 || cr.threshold(model.CertificateDaysLeft(prev.NotAfter, prev.CheckedAt)) != threshold) {
+		if cert.NotAfter.Before(cert.CheckedAt) {
+			logs = append(logs, fmt.Sprintf("certificate %q expired on %s",
+				cert.Subject, cert.NotAfter.Format(time.RFC3339)))
+		} else {
+			logs = append(logs, fmt.Sprintf("certificate %q expires in %d days (%s)",
+				cert.Subject, cert.DaysLeft, cert.NotAfter.Format(time.RFC3339)))
+		}
+	}
+	if !cert.HostnameMatch && (prev.ServiceID == 0 || prev.HostnameMatch) {
+		logs = append(logs, fmt.Sprintf("certificate %q doesn't match the hostname, valid for %v", cert.Subject, cert.SANs))
+	}
+
+	for _, log := range logs {
+		err := cr.IErrorReportsRepo.Add(ctx, model.ErrorReport{
+			ServiceID:   cert.ServiceID,
+			ServiceName: cert.ServiceName,
+			Log:         log,
+			OccurredAt:  cert.CheckedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// threshold returns the smallest warning threshold days falls under, or -1
+// when it's above all of them. Expired certificates are at threshold 0.
+func (cr *CertificateRecorder) threshold(days int) int {
+	if days < 0 {
+		return 0
+	}
+	threshold := -1
+	for _, t := range cr.WarnDays {
+		if days <= t && (threshold < 0 || t < threshold) {
+			threshold = t
+		}
+	}
+	return threshold
+}
+
+// IncidentRecorder opens an incident when a service starts failing, adds
+// every further failure and error report to it and resolves it on recovery.
+// It must run after ErrorReportRecorder so the report of the current check
+// is linked.
+type IncidentRecorder struct {
+	IIncidentsRepo repository.IIncidentsRepository
+}
+
+func (ir *IncidentRecorder) Record(ctx context.Context, service model.Service, result model.CheckResult) error {
+	incident, err := ir.IIncidentsRepo.GetUnresolved(ctx, result.ServiceID)
+	if err != nil {
+		return err
+	}
+
+	if result.Success {
+		if incident.ID == 0 {
+			return nil
+		}
+		if err := ir.IIncidentsRepo.Resolve(ctx, incident.ID, result.CheckedAt); err != nil {
+			return err
+		}
+		return ir.IIncidentsRepo.AddEvent(ctx, model.IncidentEvent{
+			IncidentID: incident.ID,
+			Kind:       model.EventResolved,
+			Message:    "service recovered",
+			CreatedAt:  result.CheckedAt,
+		})
+	}
+
+	if incident.ID == 0 {
+		incident = model.Incident{
+			ServiceID: result.ServiceID,
+			Title:     result.ServiceName + " is down",
+			OpenedAt:  result.CheckedAt,
+		}
+		incident.ID, err = ir.IIncidentsRepo.Open(ctx, incident)
+		if err != nil {
+			return err
+		}
+		err = ir.IIncidentsRepo.AddEvent(ctx, model.IncidentEvent{
+			IncidentID: incident.ID,
+			Kind:       model.EventOpened,
+			CreatedAt:  result.CheckedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ir.IIncidentsRepo.AddEvent(ctx, model.IncidentEvent{
+		IncidentID: incident.ID,
+		Kind:       model.EventCheckFailed,
+		Message:    result.Error,
+		CreatedAt:  result.CheckedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return ir.IIncidentsRepo.LinkErrorReports(ctx, incident)
+}
+
+func logResult(ctx context.Context, service model.Service, result model.CheckResult) error {
+	tags := midlog.Tags(
+		midlog.Str("service", result.ServiceName),
+		midlog.Int("status", result.StatusCode),
+		midlog.Int64("latency", result.Latency),
+	)
+	if result.Success {
+		logger.DebugT(tags, "Check succeeded")
+	} else {
+		logger.WarnTF(tags, "Check failed: %s", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"monitoring/internal/model"
+	"net"
+	"strings"
+	"time"
+)
+
+// TCPProber opens a connection to the service address and optionally runs
+// a send/expect exchange over it.
+type TCPProber struct {
+	Dialer net.Dialer
+}
+
+func (tp *TCPProber) Probe(ctx context.Context, service model.Service) (result model.CheckResult) {
+	if service.Address == nil || *service.Address == "" {
+		result.Error = "service has no address"
+		return
+	}
+	var cfg model.TCPCheckConfig
+	if service.Config != nil && service.Config.TCP != nil {
+		cfg = *service.Config.TCP
+	}
+
+	start := time.Now()
+	defer func() {
+		result.Latency = time.Since(start).Milliseconds()
+	}()
+
+	conn, err := tp.Dialer.DialContext(ctx, "tcp", *service.Address)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if cfg.Send != "" {
+		if _, err := io.WriteString(conn, cfg.Send); err != nil {
+			result.Error = err.Error()
+			return
+		}
+	}
+
+	if cfg.Expect != "" {
+		reply, err := readUntil(conn, cfg.Expect)
+		result.ResponseSize = int64(len(reply))
+		if !strings.Contains(reply, cfg.Expect) {
+			if err == nil {
+				err = errors.New("connection closed")
+			}
+			result.Error = fmt.Sprintf("expected %q in reply: %v", cfg.Expect, err)
+			return
+		}
+	}
+
+	result.Success = true
+	return
+}
+
+// readUntil reads from r until expect shows up, the reply reaches
+// MaxBodySize or reading fails.
+func readUntil(r io.Reader, expect string) (string, error) {
+	var (
+		reply strings.Builder
+		buf   = make([]byte, 4096)
+	)
+	for reply.Len() < MaxBodySize {
+		n, err := r.Read(buf)
+		reply.Write(buf[:n])
+		if strings.Contains(reply.String(), expect) {
+			return reply.String(), nil
+		}
+		if err != nil {
+			return reply.String(), err
+		}
+	}
+	return reply.String(), nil
+}
@@ -0,0 +1,119 @@
+package probe
+
+import (
+	"bufio"
+	"context"
+	"monitoring/internal/model"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// echoServer replies to every line with "+OK <line>".
+func echoServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				conn.Write([]byte("+OK " + line))
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func tcpService(address string, cfg *model.TCPCheckConfig) model.Service {
+	return model.Service{Address: &address, Config: &model.CheckConfig{TCP: cfg}}
+}
+
+func TestTCPProber(t *testing.T) {
+	address := echoServer(t)
+
+	tests := []struct {
+		name    string
+		cfg     *model.TCPCheckConfig
+		success bool
+		err     string
+	}{
+		{name: "connect only", success: true},
+		{name: "send and expect", cfg: &model.TCPCheckConfig{Send: "PING\n", Expect: "+OK PING"}, success: true},
+		{name: "unexpected reply", cfg: &model.TCPCheckConfig{Send: "PING\n", Expect: "PONG"}, err: `expected "PONG"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			result := (&TCPProber{}).Probe(ctx, tcpService(address, tt.cfg))
+			if result.Success != tt.success {
+				t.Fatalf("success = %v, want %v (error %q)", result.Success, tt.success, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.err) {
+				t.Fatalf("error = %q, want it to contain %q", result.Error, tt.err)
+			}
+		})
+	}
+}
+
+func TestTCPProberExpectTimeout(t *testing.T) {
+	// the server accepts but never answers
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	result := (&TCPProber{}).Probe(ctx, tcpService(ln.Addr().String(), &model.TCPCheckConfig{Expect: "hello"}))
+	if result.Success || !strings.Contains(result.Error, `expected "hello"`) {
+		t.Fatalf("got success %v, error %q", result.Success, result.Error)
+	}
+}
+
+func TestTCPProberRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := ln.Addr().String()
+	ln.Close()
+
+	result := (&TCPProber{}).Probe(context.Background(), tcpService(address, nil))
+	if result.Success || result.Error == "" {
+		t.Fatalf("got success %v, error %q", result.Success, result.Error)
+	}
+}
+
+func TestReadUntil(t *testing.T) {
+	reply, err := readUntil(strings.NewReader("220 smtp ready\r\n"), "220")
+	if err != nil || reply != "220 smtp ready\r\n" {
+		t.Fatalf("got %q, %v", reply, err)
+	}
+	if _, err := readUntil(strings.NewReader("nope"), "220"); err == nil {
+		t.Fatal("expected an error when the reader ends first")
+	}
+}
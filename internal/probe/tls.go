@@ -0,0 +1,55 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"monitoring/internal/model"
+	"net"
+	"time"
+)
+
+// TLSProber completes a TLS handshake with the service address. The
+// certificate is recorded even when the handshake rejects it.
+type TLSProber struct {
+	Dialer net.Dialer
+}
+
+func (tp *TLSProber) Probe(ctx context.Context, service model.Service) (result model.CheckResult) {
+	if service.Address == nil || *service.Address == "" {
+		result.Error = "service has no address"
+		return
+	}
+	var cfg model.TLSCheckConfig
+	if service.Config != nil && service.Config.TLS != nil {
+		cfg = *service.Config.TLS
+	}
+
+	dialer := tls.Dialer{
+		NetDialer: &tp.Dialer,
+		Config: &tls.Config{
+			ServerName:         cfg.ServerName,
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+		},
+	}
+
+	host := cfg.ServerName
+	if host == "" {
+		host, _, _ = net.SplitHostPort(*service.Address)
+	}
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", *service.Address)
+	result.Latency = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		result.Certificate = rejectedCertificateInfo(err, host)
+		return
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	result.Certificate = certificateInfo(&state, host)
+
+	result.Success = true
+	return
+}
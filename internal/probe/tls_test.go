@@ -0,0 +1,132 @@
+package probe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"monitoring/internal/model"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func tlsService(address string, cfg *model.TLSCheckConfig) model.Service {
+	return model.Service{Address: &address, Config: &model.CheckConfig{TLS: cfg}}
+}
+
+func TestTLSProber(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	address := srv.Listener.Addr().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	t.Run("skip verify", func(t *testing.T) {
+		result := (&TLSProber{}).Probe(ctx, tlsService(address, &model.TLSCheckConfig{InsecureSkipVerify: true}))
+		if !result.Success {
+			t.Fatalf("probe failed: %s", result.Error)
+		}
+		cert := result.Certificate
+		if cert == nil {
+			t.Fatal("no certificate recorded")
+		}
+		if !cert.HostnameMatch {
+			t.Errorf("hostname should match the test certificate, SANs %v", cert.SANs)
+		}
+		if cert.DaysLeft <= 0 {
+			t.Errorf("days left = %d", cert.DaysLeft)
+		}
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		result := (&TLSProber{}).Probe(ctx, tlsService(address, nil))
+		if result.Success || result.Error == "" {
+			t.Fatalf("got success %v, error %q", result.Success, result.Error)
+		}
+	})
+
+	t.Run("no address", func(t *testing.T) {
+		result := (&TLSProber{}).Probe(ctx, model.Service{})
+		if result.Success || result.Error != "service has no address" {
+			t.Fatalf("got success %v, error %q", result.Success, result.Error)
+		}
+	})
+}
+
+// expiredServer serves https with a self-signed certificate for 127.0.0.1
+// that expired yesterday.
+func expiredServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "expired.test"},
+		DNSNames:     []string{"expired.test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-48 * time.Hour),
+		NotAfter:     time.Now().Add(-24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTLSProberRecordsRejectedCertificate(t *testing.T) {
+	srv := expiredServer(t)
+	address := srv.Listener.Addr().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	result := (&TLSProber{}).Probe(ctx, tlsService(address, nil))
+	if result.Success {
+		t.Fatal("handshake with an expired certificate should fail")
+	}
+	if result.Certificate == nil {
+		t.Fatalf("rejected certificate wasn't recorded, error %q", result.Error)
+	}
+	if result.Certificate.DaysLeft >= 0 {
+		t.Errorf("days left = %d, want negative", result.Certificate.DaysLeft)
+	}
+
+	result = (&TLSProber{}).Probe(ctx, tlsService(address, &model.TLSCheckConfig{ServerName: "other.test"}))
+	if result.Success || result.Certificate == nil {
+		t.Fatalf("got success %v, certificate %v", result.Success, result.Certificate)
+	}
+	if result.Certificate.HostnameMatch {
+		t.Error("certificate for expired.test shouldn't match other.test")
+	}
+}
+
+func TestHTTPProberRecordsRejectedCertificate(t *testing.T) {
+	srv := expiredServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	result := (&HTTPProber{}).Probe(ctx, model.Service{Address: &srv.URL})
+	if result.Success || result.Certificate == nil {
+		t.Fatalf("got success %v, certificate %v, error %q", result.Success, result.Certificate, result.Error)
+	}
+	if result.Certificate.Subject != "CN=expired.test" {
+		t.Errorf("subject = %q", result.Certificate.Subject)
+	}
+}
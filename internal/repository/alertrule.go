@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+	"time"
+)
+
+type IAlertRulesRepository interface {
+	Add(ctx context.Context, rule model.AlertRule) error
+	Update(ctx context.Context, rule model.AlertRule) error
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, serviceID int) ([]model.AlertRule, error)
+	Get(ctx context.Context, id int) (rule model.AlertRule, found bool, err error)
+	SetFiring(ctx context.Context, id int, firing bool, at time.Time) error
+}
+
+type AlertRulesRepository struct {
+	DB postgres.IPostgres
+}
+
+func (ar *AlertRulesRepository) Add(ctx context.Context, rule model.AlertRule) error {
+	_, err := ar.DB.ExecContext(ctx, `
+		INSERT INTO alert_rules (service_id, kind, threshold, window_seconds, enabled)
+		VALUES ($1, $2, $3, $4, $5)`,
+		rule.ServiceID, rule.Kind, rule.Threshold, rule.Window, rule.Enabled)
+	return err
+}
+
+func (ar *AlertRulesRepository) Update(ctx context.Context, rule model.AlertRule) error {
+	res, err := ar.DB.ExecContext(ctx, `
+		UPDATE alert_rules SET kind = $1, threshold = $2, window_seconds = $3, enabled = $4
+		WHERE id = $5`,
+		rule.Kind, rule.Threshold, rule.Window, rule.Enabled, rule.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("alert rule not found")
+	}
+	return nil
+}
+
+func (ar *AlertRulesRepository) Delete(ctx context.Context, id int) error {
+	_, err := ar.DB.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1`, id)
+	return err
+}
+
+// List returns the rules of a service, or of every service when serviceID
+// is zero.
+func (ar *AlertRulesRepository) List(ctx context.Context, serviceID int) ([]model.AlertRule, error) {
+	return ar.list(ctx, "WHERE $1 = 0 OR r.service_id = $1", serviceID)
+}
+
+func (ar *AlertRulesRepository) Get(ctx context.Context, id int) (model.AlertRule, bool, error) {
+	rules, err := ar.list(ctx, "WHERE r.id = $1", id)
+	if err != nil || len(rules) == 0 {
+		return model.AlertRule{}, false, err
+	}
+	return rules[0], true, nil
+}
+
+func (ar *AlertRulesRepository) list(ctx context.Context, where string, args ...interface{}) (rules []model.AlertRule, err error) {
+	rows, err := ar.DB.QueryContext(ctx, `
+		SELECT r.id, r.service_id, s.name, r.kind, r.threshold, r.window_seconds, r.enabled, r.firing, r.changed_at
+		FROM alert_rules r
+		JOIN services s ON s.id = r.service_id
+		`+where+`
+		ORDER BY r.service_id, r.id;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rule model.AlertRule
+		err := rows.Scan(
+			&rule.ID, &rule.ServiceID, &rule.ServiceName, &rule.Kind, &rule.Threshold, &rule.Window,
+			&rule.Enabled, &rule.Firing, &rule.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (ar *AlertRulesRepository) SetFiring(ctx context.Context, id int, firing bool, at time.Time) error {
+	_, err := ar.DB.ExecContext(ctx, `UPDATE alert_rules SET firing = $1, changed_at = $2 WHERE id = $3`, firing, at, id)
+	return err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+	"strings"
+)
+
+type IAuditRepository interface {
+	Add(ctx context.Context, event model.AuditEvent) error
+	List(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error)
+}
+
+type AuditRepository struct {
+	DB postgres.IPostgres
+}
+
+func (ar *AuditRepository) Add(ctx context.Context, event model.AuditEvent) error {
+	_, err := ar.DB.ExecContext(ctx, `
+		INSERT INTO audit_events (actor_id, actor, api_key_id, action, target_type, target, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		event.ActorID, event.Actor, event.APIKeyID, event.Action, event.TargetType, event.Target,
+		nullJSON(event.Before), nullJSON(event.After))
+	return err
+}
+
+func (ar *AuditRepository) List(ctx context.Context, filter model.AuditFilter) (events []model.AuditEvent, err error) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	if filter.Actor != "" {
+		args = append(args, filter.Actor)
+		conds = append(conds, fmt.Sprintf("actor = $%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conds = append(conds, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if filter.TargetType != "" {
+		args = append(args, filter.TargetType)
+		conds = append(conds, fmt.Sprintf("target_type = $%d", len(args)))
+	}
+	if filter.Target != "" {
+		args = append(args, filter.Target)
+		conds = append(conds, fmt.Sprintf("target = $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conds = append(conds, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conds = append(conds, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	q := `SELECT id, actor_id, actor, api_key_id, action, target_type, target, before, after, created_at FROM audit_events`
+	if len(conds) > 0 {
+		q += " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	q += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d;", len(args)-1, len(args))
+
+	rows, err := ar.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			event         model.AuditEvent
+			before, after []byte
+		)
+		err := rows.Scan(&event.ID, &event.ActorID, &event.Actor, &event.APIKeyID, &event.Action,
+			&event.TargetType, &event.Target, &before, &after, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		event.Before, event.After = before, after
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// nullJSON stores empty documents as NULL.
+func nullJSON(doc []byte) interface{} {
+	if len(doc) == 0 {
+		return nil
+	}
+	return string(doc)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ICertificatesRepository interface {
+	Upsert(ctx context.Context, cert model.Certificate) error
+	Get(ctx context.Context, serviceID int) (cert model.Certificate, err error)
+	List(ctx context.Context) ([]model.Certificate, error)
+}
+
+type CertificatesRepository struct {
+	DB postgres.IPostgres
+}
+
+func (cr *CertificatesRepository) Upsert(ctx context.Context, cert model.Certificate) error {
+	_, err := cr.DB.ExecContext(ctx, `
+		INSERT INTO service_certificates (service_id, subject, issuer, sans, not_before, not_after, hostname_match, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (service_id) DO UPDATE SET
+			subject = EXCLUDED.subject, issuer = EXCLUDED.issuer, sans = EXCLUDED.sans,
+			not_before = EXCLUDED.not_before, not_after = EXCLUDED.not_after,
+			hostname_match = EXCLUDED.hostname_match, checked_at = EXCLUDED.checked_at;
+	`, cert.ServiceID, cert.Subject, cert.Issuer, pq.Array(cert.SANs), cert.NotBefore, cert.NotAfter,
+		cert.HostnameMatch, cert.CheckedAt)
+	return err
+}
+
+// Get returns the stored certificate of a service, ServiceID is zero when
+// there is none.
+func (cr *CertificatesRepository) Get(ctx context.Context, serviceID int) (cert model.Certificate, err error) {
+	certs, err := cr.list(ctx, "WHERE c.service_id = $1", serviceID)
+	if err != nil || len(certs) == 0 {
+		return
+	}
+	return certs[0], nil
+}
+
+// List returns every stored certificate, soonest expiry first.
+func (cr *CertificatesRepository) List(ctx context.Context) ([]model.Certificate, error) {
+	return cr.list(ctx, "")
+}
+
+func (cr *CertificatesRepository) list(ctx context.Context, where string, args ...interface{}) (certs []model.Certificate, err error) {
+	rows, err := cr.DB.QueryContext(ctx, `
+		SELECT c.service_id, s.name, c.subject, c.issuer, c.sans, c.not_before, c.not_after, c.hostname_match, c.checked_at
+		FROM service_certificates c
+		JOIN services s ON s.id = c.service_id `+where+`
+		ORDER BY c.not_after;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	for rows.Next() {
+		var cert model.Certificate
+		err := rows.Scan(
+			&cert.ServiceID, &cert.ServiceName, &cert.Subject, &cert.Issuer, pq.Array(&cert.SANs),
+			&cert.NotBefore, &cert.NotAfter, &cert.HostnameMatch, &cert.CheckedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		cert.DaysLeft = model.CertificateDaysLeft(cert.NotAfter, now)
+		certs = append(certs, cert)
+	}
+
+	return certs, rows.Err()
+}
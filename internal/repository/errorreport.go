@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type IErrorReportsRepository interface {
+	Add(ctx context.Context, report model.ErrorReport) error
+	List(ctx context.Context, filter model.ErrorReportFilter) ([]model.ErrorReport, error)
+}
+
+type ErrorReportsRepository struct {
+	DB postgres.IPostgres
+}
+
+func (er *ErrorReportsRepository) Add(ctx context.Context, report model.ErrorReport) error {
+	_, err := er.DB.ExecContext(ctx, `
+		INSERT INTO error_reports (service_id, service_name, log, occurred_at)
+		VALUES (NULLIF($1, 0), $2, $3, $4)`,
+		report.ServiceID, report.ServiceName, report.Log, report.OccurredAt)
+	return err
+}
+
+// List returns the reports matching filter, newest first. Zero valued
+// filter fields are ignored.
+func (er *ErrorReportsRepository) List(ctx context.Context, filter model.ErrorReportFilter) (reports []model.ErrorReport, err error) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	if filter.ServiceName != "" {
+		args = append(args, filter.ServiceName)
+		conds = append(conds, fmt.Sprintf("service_name = $%d", len(args)))
+	}
+	if filter.ServiceNames != nil {
+		args = append(args, pq.Array(filter.ServiceNames))
+		conds = append(conds, fmt.Sprintf("service_name = ANY($%d)", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conds = append(conds, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conds = append(conds, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+
+	q := `SELECT id, COALESCE(service_id, 0), service_name, log, occurred_at FROM error_reports`
+	if len(conds) > 0 {
+		q += " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	q += fmt.Sprintf(" ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d;", len(args)-1, len(args))
+
+	rows, err := er.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report model.ErrorReport
+		err := rows.Scan(&report.ID, &report.ServiceID, &report.ServiceName, &report.Log, &report.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
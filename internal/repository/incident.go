@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type IIncidentsRepository interface {
+	Open(ctx context.Context, incident model.Incident) (int64, error)
+	Get(ctx context.Context, id int64) (model.Incident, error)
+	GetUnresolved(ctx context.Context, serviceID int) (model.Incident, error)
+	List(ctx context.Context, filter model.IncidentFilter) ([]model.Incident, error)
+	Acknowledge(ctx context.Context, id int64, by string, at time.Time) error
+	Resolve(ctx context.Context, id int64, at time.Time) error
+	AddEvent(ctx context.Context, event model.IncidentEvent) error
+	Events(ctx context.Context, id int64) ([]model.IncidentEvent, error)
+	LinkErrorReports(ctx context.Context, incident model.Incident) error
+	ErrorReports(ctx context.Context, id int64) ([]model.ErrorReport, error)
+}
+
+type IncidentsRepository struct {
+	DB postgres.IPostgres
+}
+
+func (ir *IncidentsRepository) Open(ctx context.Context, incident model.Incident) (id int64, err error) {
+	rows, err := ir.DB.QueryContext(ctx, `
+		INSERT INTO incidents (service_id, status, title, opened_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id;
+	`, incident.ServiceID, model.IncidentOpen, incident.Title, incident.OpenedAt)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
+		}
+	}
+	return id, rows.Err()
+}
+
+// Get returns the incident, ID is zero when it doesn't exist.
+func (ir *IncidentsRepository) Get(ctx context.Context, id int64) (model.Incident, error) {
+	return ir.one(ctx, "WHERE i.id = $1", id)
+}
+
+// GetUnresolved returns the open or acknowledged incident of a service, ID
+// is zero when there is none.
+func (ir *IncidentsRepository) GetUnresolved(ctx context.Context, serviceID int) (model.Incident, error) {
+	return ir.one(ctx, "WHERE i.service_id = $1 AND i.status <> 'resolved'", serviceID)
+}
+
+// List returns the incidents matching filter, newest first.
+func (ir *IncidentsRepository) List(ctx context.Context, filter model.IncidentFilter) ([]model.Incident, error) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	if filter.ServiceName != "" {
+		args = append(args, filter.ServiceName)
+		conds = append(conds, fmt.Sprintf("s.name = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("i.status = $%d", len(args)))
+	}
+	if filter.Unresolved {
+		conds = append(conds, "i.status <> 'resolved'")
+	}
+	if filter.ServiceIDs != nil {
+		args = append(args, pq.Array(filter.ServiceIDs))
+		conds = append(conds, fmt.Sprintf("i.service_id = ANY($%d)", len(args)))
+	}
+
+	q := ""
+	if len(conds) > 0 {
+		q = "WHERE " + strings.Join(conds, " AND ")
+	}
+	q += " ORDER BY i.opened_at DESC, i.id DESC"
+	// a zero limit returns every incident
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		q += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		q += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return ir.list(ctx, q, args...)
+}
+
+func (ir *IncidentsRepository) Acknowledge(ctx context.Context, id int64, by string, at time.Time) error {
+	_, err := ir.DB.ExecContext(ctx, `
+		UPDATE incidents SET status = $1, acknowledged_at = $2, acknowledged_by = $3
+		WHERE id = $4 AND status = $5`,
+		model.IncidentAcknowledged, at, by, id, model.IncidentOpen)
+	return err
+}
+
+func (ir *IncidentsRepository) Resolve(ctx context.Context, id int64, at time.Time) error {
+	_, err := ir.DB.ExecContext(ctx, `
+		UPDATE incidents SET status = $1, resolved_at = $2
+		WHERE id = $3 AND status <> $1`,
+		model.IncidentResolved, at, id)
+	return err
+}
+
+func (ir *IncidentsRepository) AddEvent(ctx context.Context, event model.IncidentEvent) error {
+	_, err := ir.DB.ExecContext(ctx, `
+		INSERT INTO incident_events (incident_id, kind, author, message, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5)`,
+		event.IncidentID, event.Kind, event.Author, event.Message, event.CreatedAt)
+	return err
+}
+
+// Events returns the events of an incident in chronological order.
+func (ir *IncidentsRepository) Events(ctx context.Context, id int64) (events []model.IncidentEvent, err error) {
+	rows, err := ir.DB.QueryContext(ctx, `
+		SELECT id, incident_id, kind, COALESCE(author, ''), COALESCE(message, ''), created_at
+		FROM incident_events
+		WHERE incident_id = $1
+		ORDER BY created_at, id;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event model.IncidentEvent
+		err := rows.Scan(&event.ID, &event.IncidentID, &event.Kind, &event.Author, &event.Message, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// LinkErrorReports attaches the unlinked error reports of the service filed
+// since the incident was opened.
+func (ir *IncidentsRepository) LinkErrorReports(ctx context.Context, incident model.Incident) error {
+	_, err := ir.DB.ExecContext(ctx, `
+		UPDATE error_reports SET incident_id = $1
+		WHERE service_id = $2 AND incident_id IS NULL AND occurred_at >= $3`,
+		incident.ID, incident.ServiceID, incident.OpenedAt)
+	return err
+}
+
+func (ir *IncidentsRepository) ErrorReports(ctx context.Context, id int64) (reports []model.ErrorReport, err error) {
+	rows, err := ir.DB.QueryContext(ctx, `
+		SELECT id, COALESCE(service_id, 0), service_name, log, occurred_at
+		FROM error_reports
+		WHERE incident_id = $1
+		ORDER BY occurred_at, id;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report model.ErrorReport
+		err := rows.Scan(&report.ID, &report.ServiceID, &report.ServiceName, &report.Log, &report.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+func (ir *IncidentsRepository) one(ctx context.Context, cond string, args ...interface{}) (model.Incident, error) {
+	incidents, err := ir.list(ctx, cond+" ORDER BY i.opened_at DESC LIMIT 1", args...)
+	if err != nil || len(incidents) == 0 {
+		return model.Incident{}, err
+	}
+	return incidents[0], nil
+}
+
+func (ir *IncidentsRepository) list(ctx context.Context, cond string, args ...interface{}) (incidents []model.Incident, err error) {
+	rows, err := ir.DB.QueryContext(ctx, `
+		SELECT i.id, i.service_id, s.name, i.status, i.title, i.opened_at, i.acknowledged_at,
+			COALESCE(i.acknowledged_by, ''), i.resolved_at
+		FROM incidents i
+		JOIN services s ON s.id = i.service_id `+cond+`;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var incident model.Incident
+		err := rows.Scan(
+			&incident.ID, &incident.ServiceID, &incident.ServiceName, &incident.Status, &incident.Title,
+			&incident.OpenedAt, &incident.AcknowledgedAt, &incident.AcknowledgedBy, &incident.ResolvedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, rows.Err()
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+)
+
+type IMaintenanceRepository interface {
+	Add(ctx context.Context, window model.MaintenanceWindow) error
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context) ([]model.MaintenanceWindow, error)
+	ListForService(ctx context.Context, serviceID int) ([]model.MaintenanceWindow, error)
+}
+
+type MaintenanceRepository struct {
+	DB postgres.IPostgres
+}
+
+func (mr *MaintenanceRepository) Add(ctx context.Context, window model.MaintenanceWindow) error {
+	_, err := mr.DB.ExecContext(ctx, `
+		INSERT INTO maintenance_windows (service_id, title, starts_at, ends_at, recurrence, until)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		window.ServiceID, window.Title, window.StartsAt, window.EndsAt, window.Recurrence, window.Until)
+	return err
+}
+
+func (mr *MaintenanceRepository) Delete(ctx context.Context, id int) error {
+	_, err := mr.DB.ExecContext(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id)
+	return err
+}
+
+func (mr *MaintenanceRepository) List(ctx context.Context) ([]model.MaintenanceWindow, error) {
+	return mr.list(ctx, "")
+}
+
+// ListForService returns the windows of a service including the ones that
+// apply to every service.
+func (mr *MaintenanceRepository) ListForService(ctx context.Context, serviceID int) ([]model.MaintenanceWindow, error) {
+	return mr.list(ctx, "WHERE m.service_id IS NULL OR m.service_id = $1", serviceID)
+}
+
+func (mr *MaintenanceRepository) list(ctx context.Context, cond string, args ...interface{}) (windows []model.MaintenanceWindow, err error) {
+	rows, err := mr.DB.QueryContext(ctx, `
+		SELECT m.id, m.service_id, COALESCE(s.name, ''), m.title, m.starts_at, m.ends_at, m.recurrence, m.until
+		FROM maintenance_windows m
+		LEFT JOIN services s ON s.id = m.service_id `+cond+`
+		ORDER BY m.starts_at;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var window model.MaintenanceWindow
+		err := rows.Scan(
+			&window.ID, &window.ServiceID, &window.ServiceName, &window.Title, &window.StartsAt, &window.EndsAt,
+			&window.Recurrence, &window.Until,
+		)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+
+	return windows, rows.Err()
+}
@@ -13,10 +13,15 @@ import (
 type IServicesRepository interface {
 	Add(ctx context.Context, service model.Service, userIds []int) error
 	GetUserService(ctx context.Context, serviceName string, userID, roleId int) (service model.Service, err error)
+	GetByName(ctx context.Context, serviceName string) (service model.Service, err error)
+	GetByID(ctx context.Context, serviceID int) (service model.Service, err error)
 	GetUserServices(ctx context.Context, roleID int, userId int) (serviceRes []model.Service, err error)
 	List(ctx context.Context) ([]model.Service, error)
+	ListPublic(ctx context.Context) ([]model.Service, error)
 	Update(ctx context.Context, service model.Service) error
 	Delete(ctx context.Context, service model.Service) error
+	SetExecutionTime(ctx context.Context, serviceID int, executionTime int64) error
+	UpdateErrorEstimate(ctx context.Context, serviceID int, sample, alpha float64) (float64, error)
 }
 
 type ServicesRepository struct {
@@ -41,9 +46,11 @@ var bodyHeader_deserializer = func(header, body string) (map[string]string, map[
 
 func (sr *ServicesRepository) Add(ctx context.Context, service model.Service, userIds []int) error {
 	_, err := sr.DB.ExecContext(ctx, `
-		INSERT INTO services (name, address, method, header, body,  access_level, execution_time)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`, service.Name, service.Address, service.Method, service.Header, service.Body,
-		service.AccessLevel, service.ExecutionTime)
+		INSERT INTO services (name, address, method, header, body,  access_level, execution_time, check_interval, assertions,
+			check_type, check_config, public_status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, COALESCE(NULLIF($10, ''), 'http'), $11, COALESCE($12, FALSE))`, service.Name,
+		service.Address, service.Method, service.Header, service.Body, service.AccessLevel, service.ExecutionTime,
+		service.Interval, service.Assertions, service.Type, service.Config, service.Public)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -62,9 +69,9 @@ func (sr *ServicesRepository) Add(ctx context.Context, service model.Service, us
 
 func (sr *ServicesRepository) GetUserService(ctx context.Context, serviceName string, userID, roleId int) (service model.Service, err error) {
 	row, err := sr.DB.QueryContext(ctx, `
-		select s.name, s.address, s.method, header, body ,s.access_level, s.execution_time, s.error_estimate	
+		select s.id, s.name, s.address, s.method, header, body ,s.access_level, s.execution_time, s.error_estimate
 		from services s
-		join user_services us on s.id = us.service_id
+		join user_service_access us on s.id = us.service_id
 		where s.name=$1 and us.user_id=$2 and (s.access_level <=$3 OR s.access_level = 1);
 	`, serviceName, userID, roleId)
 	if err != nil {
@@ -74,7 +81,7 @@ func (sr *ServicesRepository) GetUserService(ctx context.Context, serviceName st
 	var header, body *string
 	for row.Next() {
 		err := row.Scan(
-			&service.Name, &service.Address, &service.Method, &header, &body,
+			&service.ID, &service.Name, &service.Address, &service.Method, &header, &body,
 			&service.AccessLevel, &service.ExecutionTime, &service.ErrorEstimate,
 		)
 		if err != nil {
@@ -102,12 +109,30 @@ func (sr *ServicesRepository) GetUserService(ctx context.Context, serviceName st
 	return
 }
 
+// GetByName returns the service regardless of user assignments, callers
+// must do their own access checks.
+func (sr *ServicesRepository) GetByName(ctx context.Context, serviceName string) (service model.Service, err error) {
+	services, err := sr.list(ctx, "WHERE name = $1", serviceName)
+	if err != nil || len(services) == 0 {
+		return
+	}
+	return services[0], nil
+}
+
+func (sr *ServicesRepository) GetByID(ctx context.Context, serviceID int) (service model.Service, err error) {
+	services, err := sr.list(ctx, "WHERE id = $1", serviceID)
+	if err != nil || len(services) == 0 {
+		return
+	}
+	return services[0], nil
+}
+
 func (sr *ServicesRepository) GetUserServices(ctx context.Context, roleID int, userId int) (serviceRes []model.Service, err error) {
 
 	q := `
-		select s.name, s.address, s.method, header, body ,s.access_level, s.execution_time
+		select s.name, s.address, s.method, header, body ,s.access_level, s.execution_time, s.error_estimate
 		from services s
-		join user_services us on s.id = us.service_id
+		join user_service_access us on s.id = us.service_id
 		where us.user_id = $1 and (s.access_level <= $2 OR s.access_level = 1);
 	`
 	rows, err := sr.DB.QueryContext(ctx, q, userId, roleID)
@@ -122,7 +147,7 @@ func (sr *ServicesRepository) GetUserServices(ctx context.Context, roleID int, u
 		var service model.Service
 		err := rows.Scan(
 			&service.Name, &service.Address, &service.Method, &header, &body,
-			&service.AccessLevel, &service.ExecutionTime,
+			&service.AccessLevel, &service.ExecutionTime, &service.ErrorEstimate,
 		)
 		if err != nil {
 			log.Fatal(err)
@@ -150,9 +175,24 @@ func (sr *ServicesRepository) GetUserServices(ctx context.Context, roleID int, u
 }
 
 func (sr *ServicesRepository) List(ctx context.Context) (services []model.Service, err error) {
-	q := `SELECT * FROM services`
+	return sr.list(ctx, "")
+}
+
+// ListPublic returns the services shown on the public status page, they
+// must be opted in and have the Demo access level.
+func (sr *ServicesRepository) ListPublic(ctx context.Context) ([]model.Service, error) {
+	return sr.list(ctx, "WHERE public_status AND access_level = $1", model.Demo)
+}
+
+func (sr *ServicesRepository) list(ctx context.Context, where string, args ...interface{}) (services []model.Service, err error) {
+	q := `
+		SELECT id, name, address, method, header, body, access_level, execution_time, error_estimate, check_interval,
+			assertions, check_type, check_config, public_status
+		FROM services ` + where + `
+		ORDER BY id;
+	`
 
-	rows, err := sr.DB.QueryContext(ctx, q)
+	rows, err := sr.DB.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -160,14 +200,49 @@ func (sr *ServicesRepository) List(ctx context.Context) (services []model.Servic
 	defer rows.Close()
 	for rows.Next() {
 		var service model.Service
-		err := rows.Scan(&service.Name, &service.Address, &service.Method, &service.Header, &service.Body, &service.AccessLevel, &service.ExecutionTime)
+		var header, body, assertions, checkConfig *string
+		err := rows.Scan(
+			&service.ID, &service.Name, &service.Address, &service.Method, &header, &body,
+			&service.AccessLevel, &service.ExecutionTime, &service.ErrorEstimate, &service.Interval,
+			&assertions, &service.Type, &checkConfig, &service.Public,
+		)
 		if err != nil {
 			return nil, err
 		}
+
+		if checkConfig != nil {
+			err = json.Unmarshal([]byte(*checkConfig), &service.Config)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if assertions != nil {
+			err = json.Unmarshal([]byte(*assertions), &service.Assertions)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if header == nil {
+			header = new(string)
+			*header = "{}"
+		}
+		if body == nil {
+			body = new(string)
+			*body = "{}"
+		}
+
+		h, b, err := bodyHeader_deserializer(*header, *body)
+		if err != nil {
+			return nil, err
+		}
+		service.Header = h
+		service.Body = b
 		services = append(services, service)
 	}
 
-	return services, nil
+	return services, rows.Err()
 }
 
 func (sr *ServicesRepository) Update(ctx context.Context, service model.Service) error {
@@ -181,6 +256,11 @@ func (sr *ServicesRepository) Update(ctx context.Context, service model.Service)
 	serviceBody := service.Body
 	serviceAccessLevel := service.AccessLevel
 	serviceExecutionTime := service.ExecutionTime
+	serviceInterval := service.Interval
+	serviceAssertions := service.Assertions
+	serviceType := service.Type
+	serviceConfig := service.Config
+	servicePublic := service.Public
 
 	// check which fields have been filled
 	var fields []string
@@ -205,6 +285,21 @@ func (sr *ServicesRepository) Update(ctx context.Context, service model.Service)
 	if *serviceExecutionTime != 0 {
 		fields = append(fields, "executiontime")
 	}
+	if serviceInterval != nil && *serviceInterval != "" {
+		fields = append(fields, "check_interval")
+	}
+	if serviceAssertions != nil {
+		fields = append(fields, "assertions")
+	}
+	if serviceType != nil && *serviceType != "" {
+		fields = append(fields, "check_type")
+	}
+	if serviceConfig != nil {
+		fields = append(fields, "check_config")
+	}
+	if servicePublic != nil {
+		fields = append(fields, "public_status")
+	}
 
 	// write query based on fields
 	switch {
@@ -237,6 +332,16 @@ func (sr *ServicesRepository) Update(ctx context.Context, service model.Service)
 				values = append(values, serviceAccessLevel)
 			case "executiontime":
 				values = append(values, serviceExecutionTime)
+			case "check_interval":
+				values = append(values, serviceInterval)
+			case "assertions":
+				values = append(values, serviceAssertions)
+			case "check_type":
+				values = append(values, serviceType)
+			case "check_config":
+				values = append(values, serviceConfig)
+			case "public_status":
+				values = append(values, servicePublic)
 			}
 		}
 		values = append(values, serviceName)
@@ -274,6 +379,16 @@ func (sr *ServicesRepository) Update(ctx context.Context, service model.Service)
 				values = append(values, serviceAccessLevel)
 			case "executiontime":
 				values = append(values, serviceExecutionTime)
+			case "check_interval":
+				values = append(values, serviceInterval)
+			case "assertions":
+				values = append(values, serviceAssertions)
+			case "check_type":
+				values = append(values, serviceType)
+			case "check_config":
+				values = append(values, serviceConfig)
+			case "public_status":
+				values = append(values, servicePublic)
 			}
 
 		}
@@ -303,3 +418,30 @@ func (sr *ServicesRepository) Delete(ctx context.Context, service model.Service)
 	}
 	return nil
 }
+
+func (sr *ServicesRepository) SetExecutionTime(ctx context.Context, serviceID int, executionTime int64) error {
+	_, err := sr.DB.ExecContext(ctx, `UPDATE services SET execution_time = $1 WHERE id = $2;`, executionTime, serviceID)
+	return err
+}
+
+// UpdateErrorEstimate folds sample into the exponentially weighted error
+// rate of the service and returns the new value. It's done in a single
+// statement so concurrent checks can't lose updates. The parameters are
+// cast as postgres can't infer the type of an untyped product.
+func (sr *ServicesRepository) UpdateErrorEstimate(ctx context.Context, serviceID int, sample, alpha float64) (estimate float64, err error) {
+	rows, err := sr.DB.QueryContext(ctx, `
+		UPDATE services SET error_estimate = $1::float8 * $2::float8 + (1 - $1::float8) * COALESCE(error_estimate, 0)
+		WHERE id = $3
+		RETURNING error_estimate;
+	`, alpha, sample, serviceID)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.Scan(&estimate); err != nil {
+			return 0, err
+		}
+	}
+	return estimate, rows.Err()
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+	"time"
+)
+
+type IServiceChecksRepository interface {
+	Add(ctx context.Context, check model.CheckResult) error
+	List(ctx context.Context, serviceID int, from, to time.Time) ([]model.CheckResult, error)
+	LatencyStats(ctx context.Context, serviceID int, from, to time.Time, buckets int) (model.LatencyStats, error)
+	Latest(ctx context.Context, serviceID int, n int) ([]model.CheckResult, error)
+	CountFailures(ctx context.Context, serviceID int, from, to time.Time) (total, failed int64, err error)
+}
+
+type ServiceChecksRepository struct {
+	DB postgres.IPostgres
+}
+
+func (scr *ServiceChecksRepository) Add(ctx context.Context, check model.CheckResult) error {
+	_, err := scr.DB.ExecContext(ctx, `
+		INSERT INTO service_checks (service_id, checked_at, success, status_code, latency_ms, response_size, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		check.ServiceID, check.CheckedAt, check.Success, check.StatusCode, check.Latency, check.ResponseSize, check.Error)
+	return err
+}
+
+// List returns the checks of a service in [from, to) ordered by time.
+func (scr *ServiceChecksRepository) List(ctx context.Context, serviceID int, from, to time.Time) (checks []model.CheckResult, err error) {
+	return scr.list(ctx, `
+		WHERE c.service_id = $1 AND c.checked_at >= $2 AND c.checked_at < $3
+		ORDER BY c.checked_at`, serviceID, from, to)
+}
+
+// Latest returns the last n checks of a service, newest first.
+func (scr *ServiceChecksRepository) Latest(ctx context.Context, serviceID int, n int) (checks []model.CheckResult, err error) {
+	return scr.list(ctx, `
+		WHERE c.service_id = $1
+		ORDER BY c.checked_at DESC
+		LIMIT $2`, serviceID, n)
+}
+
+func (scr *ServiceChecksRepository) list(ctx context.Context, cond string, args ...interface{}) (checks []model.CheckResult, err error) {
+	rows, err := scr.DB.QueryContext(ctx, `
+		SELECT c.service_id, s.name, c.checked_at, c.success, COALESCE(c.status_code, 0), c.latency_ms,
+			COALESCE(c.response_size, 0), COALESCE(c.error, '')
+		FROM service_checks c
+		JOIN services s ON s.id = c.service_id `+cond+`;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var check model.CheckResult
+		err := rows.Scan(
+			&check.ServiceID, &check.ServiceName, &check.CheckedAt, &check.Success, &check.StatusCode,
+			&check.Latency, &check.ResponseSize, &check.Error,
+		)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, rows.Err()
+}
+
+// LatencyStats aggregates the latency of the successful checks of a service
+// in [from, to) and splits [min, max] into equally wide histogram buckets.
+func (scr *ServiceChecksRepository) LatencyStats(ctx context.Context, serviceID int, from, to time.Time, buckets int) (stats model.LatencyStats, err error) {
+	stats.From = from
+	stats.To = to
+
+	rows, err := scr.DB.QueryContext(ctx, `
+		SELECT count(*), COALESCE(min(latency_ms), 0), COALESCE(max(latency_ms), 0), COALESCE(avg(latency_ms), 0),
+			COALESCE(percentile_cont(0.50) WITHIN GROUP (ORDER BY latency_ms), 0),
+			COALESCE(percentile_cont(0.90) WITHIN GROUP (ORDER BY latency_ms), 0),
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms), 0),
+			COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY latency_ms), 0)
+		FROM service_checks
+		WHERE service_id = $1 AND checked_at >= $2 AND checked_at < $3 AND success;
+	`, serviceID, from, to)
+	if err != nil {
+		return stats, err
+	}
+	for rows.Next() {
+		err = rows.Scan(&stats.Count, &stats.Min, &stats.Max, &stats.Mean, &stats.P50, &stats.P90, &stats.P95, &stats.P99)
+		if err != nil {
+			rows.Close()
+			return stats, err
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil || stats.Count == 0 || buckets <= 0 {
+		return stats, err
+	}
+
+	lo, hi := float64(stats.Min), float64(stats.Max+1)
+	width := (hi - lo) / float64(buckets)
+	stats.Histogram = make([]model.LatencyBucket, buckets)
+	for i := range stats.Histogram {
+		stats.Histogram[i].From = lo + float64(i)*width
+		stats.Histogram[i].To = lo + float64(i+1)*width
+	}
+
+	rows, err = scr.DB.QueryContext(ctx, `
+		SELECT width_bucket(latency_ms, $4, $5, $6) AS bucket, count(*)
+		FROM service_checks
+		WHERE service_id = $1 AND checked_at >= $2 AND checked_at < $3 AND success
+		GROUP BY bucket;
+	`, serviceID, from, to, lo, hi, buckets)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var bucket int
+		var count int64
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return stats, err
+		}
+		if bucket >= 1 && bucket <= buckets {
+			stats.Histogram[bucket-1].Count += count
+		}
+	}
+
+	return stats, rows.Err()
+}
+
+// CountFailures counts the checks and failed checks of a service in
+// [from, to).
+func (scr *ServiceChecksRepository) CountFailures(ctx context.Context, serviceID int, from, to time.Time) (total, failed int64, err error) {
+	rows, err := scr.DB.QueryContext(ctx, `
+		SELECT count(*), count(*) FILTER (WHERE NOT success)
+		FROM service_checks
+		WHERE service_id = $1 AND checked_at >= $2 AND checked_at < $3;
+	`, serviceID, from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.Scan(&total, &failed); err != nil {
+			return 0, 0, err
+		}
+	}
+	return total, failed, rows.Err()
+}
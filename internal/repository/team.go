@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+
+	"github.com/lib/pq"
+)
+
+type ITeamsRepository interface {
+	Add(ctx context.Context, team model.Team) error
+	Update(ctx context.Context, team model.Team) error
+	Delete(ctx context.Context, id int) error
+	Get(ctx context.Context, id int) (team model.Team, found bool, err error)
+	List(ctx context.Context) ([]model.Team, error)
+	AddMember(ctx context.Context, member model.TeamMember) error
+	RemoveMember(ctx context.Context, member model.TeamMember) error
+	AddService(ctx context.Context, teamService model.TeamService) error
+	RemoveService(ctx context.Context, teamService model.TeamService) error
+}
+
+type TeamsRepository struct {
+	DB postgres.IPostgres
+}
+
+func (tr *TeamsRepository) Add(ctx context.Context, team model.Team) error {
+	_, err := tr.DB.ExecContext(ctx, `
+		INSERT INTO teams (name, description)
+		VALUES ($1, $2)`, team.Name, team.Description)
+	return err
+}
+
+func (tr *TeamsRepository) Update(ctx context.Context, team model.Team) error {
+	res, err := tr.DB.ExecContext(ctx, `
+		UPDATE teams SET name = $1, description = $2
+		WHERE id = $3`, team.Name, team.Description, team.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("team not found")
+	}
+	return nil
+}
+
+func (tr *TeamsRepository) Delete(ctx context.Context, id int) error {
+	_, err := tr.DB.ExecContext(ctx, `DELETE FROM teams WHERE id = $1`, id)
+	return err
+}
+
+func (tr *TeamsRepository) Get(ctx context.Context, id int) (model.Team, bool, error) {
+	teams, err := tr.list(ctx, "WHERE t.id = $1", id)
+	if err != nil || len(teams) == 0 {
+		return model.Team{}, false, err
+	}
+	return teams[0], true, nil
+}
+
+func (tr *TeamsRepository) List(ctx context.Context) ([]model.Team, error) {
+	return tr.list(ctx, "")
+}
+
+func (tr *TeamsRepository) list(ctx context.Context, where string, args ...interface{}) (teams []model.Team, err error) {
+	rows, err := tr.DB.QueryContext(ctx, `
+		SELECT t.id, t.name, t.description, t.created_at,
+			ARRAY(SELECT user_id FROM team_members WHERE team_id = t.id ORDER BY user_id),
+			ARRAY(SELECT service_id FROM team_services WHERE team_id = t.id ORDER BY service_id)
+		FROM teams t `+where+`
+		ORDER BY t.name;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			team                model.Team
+			userIDs, serviceIDs []int64
+		)
+		err := rows.Scan(&team.ID, &team.Name, &team.Description, &team.CreatedAt,
+			pq.Array(&userIDs), pq.Array(&serviceIDs))
+		if err != nil {
+			return nil, err
+		}
+		team.UserIDs = toInts(userIDs)
+		team.ServiceIDs = toInts(serviceIDs)
+		teams = append(teams, team)
+	}
+
+	return teams, rows.Err()
+}
+
+func (tr *TeamsRepository) AddMember(ctx context.Context, member model.TeamMember) error {
+	_, err := tr.DB.ExecContext(ctx, `
+		INSERT INTO team_members (team_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, member.TeamID, member.UserID)
+	return err
+}
+
+func (tr *TeamsRepository) RemoveMember(ctx context.Context, member model.TeamMember) error {
+	_, err := tr.DB.ExecContext(ctx, `
+		DELETE FROM team_members
+		WHERE team_id = $1 AND user_id = $2`, member.TeamID, member.UserID)
+	return err
+}
+
+func (tr *TeamsRepository) AddService(ctx context.Context, teamService model.TeamService) error {
+	_, err := tr.DB.ExecContext(ctx, `
+		INSERT INTO team_services (team_id, service_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, teamService.TeamID, teamService.ServiceID)
+	return err
+}
+
+func (tr *TeamsRepository) RemoveService(ctx context.Context, teamService model.TeamService) error {
+	_, err := tr.DB.ExecContext(ctx, `
+		DELETE FROM team_services
+		WHERE team_id = $1 AND service_id = $2`, teamService.TeamID, teamService.ServiceID)
+	return err
+}
+
+func toInts(values []int64) []int {
+	ints := make([]int, len(values))
+	for i, v := range values {
+		ints[i] = int(v)
+	}
+	return ints
+}
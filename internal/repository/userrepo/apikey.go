@@ -0,0 +1,105 @@
+package userrepo
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+
+	"github.com/lib/pq"
+)
+
+type IAPIKeyRepo interface {
+	Add(ctx context.Context, key model.APIKey, hash string) error
+	List(ctx context.Context) ([]model.APIKey, error)
+	Get(ctx context.Context, id int) (key model.APIKey, found bool, err error)
+	GetByHash(ctx context.Context, hash string) (key model.APIKey, found bool, err error)
+	Revoke(ctx context.Context, id int) error
+	Touch(ctx context.Context, id int) error
+}
+
+type APIKeyRepo struct {
+	DB postgres.IPostgres
+}
+
+func (ar *APIKeyRepo) Add(ctx context.Context, key model.APIKey, hash string) error {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+	_, err := ar.DB.ExecContext(ctx, `
+		INSERT INTO api_keys (name, prefix, key_hash, user_id, username, scopes, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		key.Name, key.Prefix, hash, key.UserID, key.Username, pq.Array(scopes), key.CreatedBy, key.ExpiresAt)
+	return err
+}
+
+func (ar *APIKeyRepo) List(ctx context.Context) ([]model.APIKey, error) {
+	return ar.list(ctx, "")
+}
+
+func (ar *APIKeyRepo) Get(ctx context.Context, id int) (model.APIKey, bool, error) {
+	keys, err := ar.list(ctx, "WHERE id = $1", id)
+	if err != nil || len(keys) == 0 {
+		return model.APIKey{}, false, err
+	}
+	return keys[0], true, nil
+}
+
+func (ar *APIKeyRepo) GetByHash(ctx context.Context, hash string) (model.APIKey, bool, error) {
+	keys, err := ar.list(ctx, "WHERE key_hash = $1", hash)
+	if err != nil || len(keys) == 0 {
+		return model.APIKey{}, false, err
+	}
+	return keys[0], true, nil
+}
+
+func (ar *APIKeyRepo) list(ctx context.Context, where string, args ...interface{}) (keys []model.APIKey, err error) {
+	rows, err := ar.DB.QueryContext(ctx, `
+		SELECT id, name, prefix, user_id, username, scopes, created_by, created_at, expires_at, revoked_at, last_used_at
+		FROM api_keys `+where+`
+		ORDER BY id;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key    model.APIKey
+			scopes []string
+		)
+		err := rows.Scan(&key.ID, &key.Name, &key.Prefix, &key.UserID, &key.Username, pq.Array(&scopes),
+			&key.CreatedBy, &key.CreatedAt, &key.ExpiresAt, &key.RevokedAt, &key.LastUsedAt)
+		if err != nil {
+			return nil, err
+		}
+		key.Scopes = make([]model.Permission, len(scopes))
+		for i, scope := range scopes {
+			key.Scopes[i] = model.Permission(scope)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (ar *APIKeyRepo) Revoke(ctx context.Context, id int) error {
+	res, err := ar.DB.ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at = now()
+		WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("api key not found")
+	}
+	return nil
+}
+
+// Touch records that the key was just used.
+func (ar *APIKeyRepo) Touch(ctx context.Context, id int) error {
+	_, err := ar.DB.ExecContext(ctx, `UPDATE api_keys SET last_used_at = now() WHERE id = $1`, id)
+	return err
+}
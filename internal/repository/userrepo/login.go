@@ -20,14 +20,14 @@ type LoginRepo struct {
 
 func (lr *LoginRepo) Auth(ctx context.Context, username, password string) (user model.LoginResult, err error) {
 
-	q := `SELECT username, password, role FROM users WHERE username = $1`
+	q := `SELECT id, username, password, role FROM users WHERE username = $1`
 	row, err := lr.DB.QueryContext(ctx, q, username)
 	if err != nil {
 		return model.LoginResult{}, err
 	}
 	defer row.Close()
 	for row.Next() {
-		err = row.Scan(&user.Username, &user.Password, &user.Role)
+		err = row.Scan(&user.ID, &user.Username, &user.Password, &user.Role)
 		if err != nil {
 			return model.LoginResult{}, err
 		}
@@ -0,0 +1,51 @@
+package userrepo
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+)
+
+type IOIDCRepo interface {
+	GetBySubject(ctx context.Context, subject string) (user model.UserRes, found bool, err error)
+	LinkSubject(ctx context.Context, userID int, subject string) error
+}
+
+type OIDCRepo struct {
+	DB postgres.IPostgres
+}
+
+func (or *OIDCRepo) GetBySubject(ctx context.Context, subject string) (model.UserRes, bool, error) {
+	rows, err := or.DB.QueryContext(ctx, `
+		SELECT id, username, role FROM users WHERE oidc_subject = $1;
+	`, subject)
+	if err != nil {
+		return model.UserRes{}, false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return model.UserRes{}, false, rows.Err()
+	}
+	var user model.UserRes
+	if err := rows.Scan(&user.UserId, &user.Username, &user.Role); err != nil {
+		return model.UserRes{}, false, err
+	}
+	return user, true, nil
+}
+
+// LinkSubject links a user to an OpenID Connect account, it fails when the
+// user is already linked to another one.
+func (or *OIDCRepo) LinkSubject(ctx context.Context, userID int, subject string) error {
+	res, err := or.DB.ExecContext(ctx, `
+		UPDATE users SET oidc_subject = $2
+		WHERE id = $1 AND (oidc_subject IS NULL OR oidc_subject = $2)`, userID, subject)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("user is linked to another account")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package userrepo
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/pkg/postgres"
+	"time"
+)
+
+type ITokenRepo interface {
+	AddRefreshToken(ctx context.Context, token model.RefreshToken, hash string) error
+	GetRefreshToken(ctx context.Context, hash string) (token model.RefreshToken, found bool, err error)
+	RevokeRefreshToken(ctx context.Context, id int64) (revoked bool, err error)
+	RevokeUserRefreshTokens(ctx context.Context, username string) error
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	RevokeUserAccessTokens(ctx context.Context, username string, before, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti, username string, issuedAt time.Time) (bool, error)
+}
+
+type TokenRepo struct {
+	DB postgres.IPostgres
+}
+
+func (tr *TokenRepo) AddRefreshToken(ctx context.Context, token model.RefreshToken, hash string) error {
+	_, err := tr.DB.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (user_id, username, token_hash, issued_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		token.UserID, token.Username, hash, token.IssuedAt, token.ExpiresAt)
+	return err
+}
+
+func (tr *TokenRepo) GetRefreshToken(ctx context.Context, hash string) (token model.RefreshToken, found bool, err error) {
+	rows, err := tr.DB.QueryContext(ctx, `
+		SELECT id, user_id, username, issued_at, expires_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1`, hash)
+	if err != nil {
+		return token, false, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&token.ID, &token.UserID, &token.Username, &token.IssuedAt, &token.ExpiresAt, &token.RevokedAt)
+		if err != nil {
+			return token, false, err
+		}
+		found = true
+	}
+	return token, found, rows.Err()
+}
+
+// RevokeRefreshToken revokes a refresh token, revoked is false when it was
+// already revoked, so only one of two concurrent refreshes succeeds.
+func (tr *TokenRepo) RevokeRefreshToken(ctx context.Context, id int64) (revoked bool, err error) {
+	res, err := tr.DB.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked_at = now()
+		WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (tr *TokenRepo) RevokeUserRefreshTokens(ctx context.Context, username string) error {
+	_, err := tr.DB.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked_at = now()
+		WHERE username = $1 AND revoked_at IS NULL`, username)
+	return err
+}
+
+func (tr *TokenRepo) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return tr.revoke(ctx, jti, nil, nil, expiresAt)
+}
+
+// RevokeUserAccessTokens revokes every access token of the user issued
+// before the given time, tokens issued at that time stay valid.
+func (tr *TokenRepo) RevokeUserAccessTokens(ctx context.Context, username string, before, expiresAt time.Time) error {
+	return tr.revoke(ctx, nil, username, before, expiresAt)
+}
+
+func (tr *TokenRepo) revoke(ctx context.Context, jti, username, before interface{}, expiresAt time.Time) error {
+	_, err := tr.DB.ExecContext(ctx, `
+		INSERT INTO revoked_tokens (jti, username, revoked_before, expires_at)
+		VALUES ($1, $2, $3, $4)`, jti, username, before, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	// entries of expired tokens are no longer needed
+	_, err = tr.DB.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at < now()`)
+	return err
+}
+
+func (tr *TokenRepo) IsRevoked(ctx context.Context, jti, username string, issuedAt time.Time) (revoked bool, err error) {
+	rows, err := tr.DB.QueryContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM revoked_tokens
+			WHERE ($1 <> '' AND jti = $1) OR (username = $2 AND revoked_before > $3)
+		)`, jti, username, issuedAt)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&revoked)
+	}
+	return revoked, err
+}
@@ -16,6 +16,7 @@ type IUserServiceRepo interface {
 	Update(ctx context.Context, newUserService model.UserService) error
 	DeleteUserServices(ctx context.Context, usrservices []model.UserService) error
 	AddUserServices(ctx context.Context, usrservices []model.UserService) error
+	AccessibleServices(ctx context.Context, userId int) ([]int, error)
 }
 
 type UserServiceRepository struct {
@@ -152,3 +153,25 @@ func (Us *UserServiceRepository) AddUserServices(ctx context.Context, usrservice
 	}
 	return nil
 }
+
+// AccessibleServices returns the ids of the services the user is assigned
+// to, directly or through one of their teams.
+func (Us *UserServiceRepository) AccessibleServices(ctx context.Context, userId int) (serviceIDs []int, err error) {
+	rows, err := Us.DB.QueryContext(ctx, `
+		SELECT service_id
+		FROM user_service_access
+		WHERE user_id = $1
+		ORDER BY service_id`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var serviceID int
+		if err := rows.Scan(&serviceID); err != nil {
+			return nil, err
+		}
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	return serviceIDs, rows.Err()
+}
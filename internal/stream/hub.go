@@ -0,0 +1,151 @@
+package stream
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"sync"
+	"time"
+)
+
+// DefaultBuffer is the number of events a subscriber may fall behind before
+// further events are dropped for it.
+const DefaultBuffer = 64
+
+// Default is the hub fed by the probe engine and served by the REST API.
+var Default = NewHub()
+
+type EventType string
+
+const (
+	// EventCheck carries every check result.
+	EventCheck EventType = "check"
+	// EventState is sent when a service goes down or recovers.
+	EventState EventType = "state"
+)
+
+type Event struct {
+	Type        EventType          `json:"type"`
+	ServiceID   int                `json:"service_id"`
+	ServiceName string             `json:"service_name"`
+	Up          bool               `json:"up"`
+	Result      *model.CheckResult `json:"result,omitempty"`
+	At          time.Time          `json:"at"`
+}
+
+// Subscription receives the events its filter accepts on C until it is
+// unsubscribed or the hub is closed.
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	filter func(serviceID int) bool
+}
+
+// Hub fans check results out to subscribers. It is a probe recorder.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	states map[int]bool
+	closed bool
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subs:   map[*Subscription]struct{}{},
+		states: map[int]bool{},
+	}
+}
+
+// Subscribe registers a subscriber for the services accepted by filter, a
+// nil filter accepts every service.
+func (h *Hub) Subscribe(filter func(serviceID int) bool) *Subscription {
+	ch := make(chan Event, DefaultBuffer)
+	sub := &Subscription{C: ch, ch: ch, filter: filter}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return sub
+	}
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+// SetFilter replaces the filter of sub, e.g. after the services its
+// subscriber may see changed.
+func (h *Hub) SetFilter(sub *Subscription, filter func(serviceID int) bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sub.filter = filter
+}
+
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Close ends every subscription so streaming handlers return, e.g. on
+// shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Publish hands event to every interested subscriber without blocking,
+// subscribers that are too slow miss it.
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		if sub.filter != nil && !sub.filter(event.ServiceID) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+func (h *Hub) Record(ctx context.Context, service model.Service, result model.CheckResult) error {
+	h.mu.Lock()
+	previous, known := h.states[result.ServiceID]
+	h.states[result.ServiceID] = result.Success
+	h.mu.Unlock()
+
+	h.Publish(Event{
+		Type:        EventCheck,
+		ServiceID:   result.ServiceID,
+		ServiceName: result.ServiceName,
+		Up:          result.Success,
+		Result:      &result,
+		At:          result.CheckedAt,
+	})
+	if known && previous != result.Success {
+		h.Publish(Event{
+			Type:        EventState,
+			ServiceID:   result.ServiceID,
+			ServiceName: result.ServiceName,
+			Up:          result.Success,
+			At:          result.CheckedAt,
+		})
+	}
+
+	return nil
+}
+
+// Forget drops the last known state of a removed service.
+func (h *Hub) Forget(service model.Service) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.states, service.ID)
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/alert"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+)
+
+type IAlertRulesUsecase interface {
+	Add(ctx context.Context, rule model.AlertRule) error
+	Update(ctx context.Context, rule model.AlertRule) error
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, serviceName string) ([]model.AlertRule, error)
+}
+
+// IRuleResolver resolves rules that stop being evaluated while firing.
+type IRuleResolver interface {
+	ResolveRule(ctx context.Context, rule model.AlertRule, reason string) error
+}
+
+type AlertRulesUsecase struct {
+	IAlertRulesRepo repository.IAlertRulesRepository
+	IServicesRepo   repository.IServicesRepository
+	IRuleResolver   IRuleResolver
+}
+
+// Add creates a rule for the service named rule.ServiceName.
+func (au *AlertRulesUsecase) Add(ctx context.Context, rule model.AlertRule) error {
+	if err := alert.ValidateRule(rule); err != nil {
+		return err
+	}
+	service, err := au.IServicesRepo.GetByName(ctx, rule.ServiceName)
+	if err != nil {
+		return err
+	}
+	if service.ID == 0 {
+		return ErrServiceNotFound
+	}
+	rule.ServiceID = service.ID
+	return au.IAlertRulesRepo.Add(ctx, rule)
+}
+
+// Update changes a rule, a firing rule is resolved and fires again on the
+// next check when its new condition still holds.
+func (au *AlertRulesUsecase) Update(ctx context.Context, rule model.AlertRule) error {
+	if err := alert.ValidateRule(rule); err != nil {
+		return err
+	}
+	prev, found, err := au.IAlertRulesRepo.Get(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("alert rule not found")
+	}
+	if err := au.IAlertRulesRepo.Update(ctx, rule); err != nil {
+		return err
+	}
+
+	reason := "rule was changed"
+	if !rule.Enabled {
+		reason = "rule was disabled"
+	}
+	return au.IRuleResolver.ResolveRule(ctx, prev, reason)
+}
+
+func (au *AlertRulesUsecase) Delete(ctx context.Context, id int) error {
+	prev, found, err := au.IAlertRulesRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := au.IAlertRulesRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	return au.IRuleResolver.ResolveRule(ctx, prev, "rule was deleted")
+}
+
+// List returns the rules of the named service, or every rule when
+// serviceName is empty.
+func (au *AlertRulesUsecase) List(ctx context.Context, serviceName string) ([]model.AlertRule, error) {
+	var serviceID int
+	if serviceName != "" {
+		service, err := au.IServicesRepo.GetByName(ctx, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		if service.ID == 0 {
+			return nil, ErrServiceNotFound
+		}
+		serviceID = service.ID
+	}
+	return au.IAlertRulesRepo.List(ctx, serviceID)
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/util/midlog"
+	"reflect"
+)
+
+var logger = midlog.LoggerForModule("usecase")
+
+const (
+	DefaultAuditLimit = 100
+	MaxAuditLimit     = 1000
+)
+
+type IAuditUsecase interface {
+	// Record stores a change made by the caller in ctx. before is nil for
+	// creations and after for deletions, changes that leave every field as
+	// it was aren't recorded.
+	Record(ctx context.Context, action model.AuditAction, targetType, target string, before, after interface{}) error
+	List(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error)
+}
+
+type AuditUsecase struct {
+	IAuditRepo repository.IAuditRepository
+}
+
+func (au *AuditUsecase) Record(ctx context.Context, action model.AuditAction, targetType, target string, before, after interface{}) error {
+	beforeDoc, afterDoc, changed, err := auditDiff(before, after)
+	if err != nil || !changed {
+		return err
+	}
+
+	event := model.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		Target:     target,
+		Before:     beforeDoc,
+		After:      afterDoc,
+	}
+	if principal := model.PrincipalFromContext(ctx); principal != nil {
+		event.ActorID = principal.UserID
+		event.Actor = principal.Name
+		if principal.APIKeyID != 0 {
+			apiKeyID := principal.APIKeyID
+			event.APIKeyID = &apiKeyID
+		}
+	}
+	return au.IAuditRepo.Add(ctx, event)
+}
+
+func (au *AuditUsecase) List(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAuditLimit
+	}
+	if filter.Limit > MaxAuditLimit {
+		filter.Limit = MaxAuditLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return au.IAuditRepo.List(ctx, filter)
+}
+
+// RecordAudit records a change with audit, usecases built without an audit
+// usecase record nothing. The change is made by then, so a failure is
+// logged rather than failing the request.
+func RecordAudit(ctx context.Context, audit IAuditUsecase, action model.AuditAction, targetType, target string, before, after interface{}) {
+	if audit == nil {
+		return
+	}
+	if err := audit.Record(ctx, action, targetType, target, before, after); err != nil {
+		AuditFailed(err, action, targetType, target)
+	}
+}
+
+// AuditFailed logs a change that couldn't be recorded, e.g. because its
+// new state couldn't be read back.
+func AuditFailed(err error, action model.AuditAction, targetType, target string) {
+	logger.ErrorEF(err, "Failed to record the %s of %s %q in the audit log", action, targetType, target)
+}
+
+// auditDiff encodes before and after as JSON, dropping the fields of two
+// objects that are equal.
+func auditDiff(before, after interface{}) (beforeDoc, afterDoc json.RawMessage, changed bool, err error) {
+	if beforeDoc, err = auditDoc(before); err != nil {
+		return nil, nil, false, err
+	}
+	if afterDoc, err = auditDoc(after); err != nil {
+		return nil, nil, false, err
+	}
+	if beforeDoc == nil || afterDoc == nil {
+		return beforeDoc, afterDoc, beforeDoc != nil || afterDoc != nil, nil
+	}
+
+	var beforeFields, afterFields map[string]interface{}
+	if json.Unmarshal(beforeDoc, &beforeFields) != nil || json.Unmarshal(afterDoc, &afterFields) != nil {
+		// not objects, keep them whole
+		if string(beforeDoc) == string(afterDoc) {
+			return nil, nil, false, nil
+		}
+		return beforeDoc, afterDoc, true, nil
+	}
+	for field, value := range beforeFields {
+		if other, ok := afterFields[field]; ok && reflect.DeepEqual(value, other) {
+			delete(beforeFields, field)
+			delete(afterFields, field)
+		}
+	}
+	if len(beforeFields) == 0 && len(afterFields) == 0 {
+		return nil, nil, false, nil
+	}
+
+	if beforeDoc, err = json.Marshal(beforeFields); err != nil {
+		return nil, nil, false, err
+	}
+	if afterDoc, err = json.Marshal(afterFields); err != nil {
+		return nil, nil, false, err
+	}
+	return beforeDoc, afterDoc, true, nil
+}
+
+func auditDoc(v interface{}) (json.RawMessage, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"testing"
+)
+
+func TestAuditDiff(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+		Role int    `json:"role"`
+		Team string `json:"team,omitempty"`
+	}
+	var nilUser *user
+
+	tests := []struct {
+		name          string
+		before, after interface{}
+		wantBefore    string
+		wantAfter     string
+		changed       bool
+	}{
+		{name: "both nil"},
+		{name: "nil pointers", before: nilUser, after: nilUser},
+		{
+			name:      "created",
+			after:     user{Name: "ann", Role: 2},
+			wantAfter: `{"name":"ann","role":2}`,
+			changed:   true,
+		},
+		{
+			name:       "deleted",
+			before:     &user{Name: "ann", Role: 2},
+			after:      nilUser,
+			wantBefore: `{"name":"ann","role":2}`,
+			changed:    true,
+		},
+		{
+			name:       "unchanged fields dropped",
+			before:     user{Name: "ann", Role: 2},
+			after:      user{Name: "ann", Role: 1},
+			wantBefore: `{"role":2}`,
+			wantAfter:  `{"role":1}`,
+			changed:    true,
+		},
+		{
+			name:       "field added",
+			before:     user{Name: "ann", Role: 2},
+			after:      user{Name: "ann", Role: 2, Team: "ops"},
+			wantBefore: `{}`,
+			wantAfter:  `{"team":"ops"}`,
+			changed:    true,
+		},
+		{
+			name:   "nothing changed",
+			before: user{Name: "ann", Role: 2},
+			after:  &user{Name: "ann", Role: 2},
+		},
+		{
+			name:   "nested values compared deeply",
+			before: map[string]interface{}{"tags": []string{"a", "b"}},
+			after:  map[string]interface{}{"tags": []string{"a", "b"}},
+		},
+		{
+			name:       "non-object values kept whole",
+			before:     []int{1, 2},
+			after:      []int{1, 3},
+			wantBefore: `[1,2]`,
+			wantAfter:  `[1,3]`,
+			changed:    true,
+		},
+		{
+			name:   "equal non-object values",
+			before: "on",
+			after:  "on",
+		},
+		{
+			name:       "object replaced by a scalar",
+			before:     user{Name: "ann"},
+			after:      42,
+			wantBefore: `{"name":"ann","role":0}`,
+			wantAfter:  `42`,
+			changed:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after, changed, err := auditDiff(tt.before, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(before) != tt.wantBefore || string(after) != tt.wantAfter || changed != tt.changed {
+				t.Errorf("got %s, %s, %v, want %s, %s, %v",
+					before, after, changed, tt.wantBefore, tt.wantAfter, tt.changed)
+			}
+		})
+	}
+}
+
+func TestAuditDiffUnencodable(t *testing.T) {
+	if _, _, _, err := auditDiff(nil, map[string]interface{}{"f": func() {}}); err == nil {
+		t.Fatal("expected an encoding error")
+	}
+}
+
+type fakeAudit struct {
+	repository.IAuditRepository
+	filter model.AuditFilter
+}
+
+func (f *fakeAudit) List(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error) {
+	f.filter = filter
+	return nil, nil
+}
+
+func TestAuditListLimits(t *testing.T) {
+	tests := []struct {
+		limit, offset int
+		wantLimit     int
+		wantOffset    int
+	}{
+		{limit: 0, wantLimit: DefaultAuditLimit},
+		{limit: -5, wantLimit: DefaultAuditLimit},
+		{limit: 20, offset: 40, wantLimit: 20, wantOffset: 40},
+		{limit: MaxAuditLimit + 1, wantLimit: MaxAuditLimit},
+		{limit: 10, offset: -1, wantLimit: 10},
+	}
+	for _, tt := range tests {
+		repo := &fakeAudit{}
+		au := &AuditUsecase{IAuditRepo: repo}
+		if _, err := au.List(context.Background(), model.AuditFilter{Limit: tt.limit, Offset: tt.offset}); err != nil {
+			t.Fatal(err)
+		}
+		if repo.filter.Limit != tt.wantLimit || repo.filter.Offset != tt.wantOffset {
+			t.Errorf("limit %d offset %d: listed with %d, %d, want %d, %d",
+				tt.limit, tt.offset, repo.filter.Limit, repo.filter.Offset, tt.wantLimit, tt.wantOffset)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+)
+
+type ICertificatesUsecase interface {
+	List(ctx context.Context) ([]model.Certificate, error)
+}
+
+type CertificatesUsecase struct {
+	ICertificatesRepo repository.ICertificatesRepository
+}
+
+func (cu *CertificatesUsecase) List(ctx context.Context) ([]model.Certificate, error) {
+	return cu.ICertificatesRepo.List(ctx)
+}
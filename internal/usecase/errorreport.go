@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+)
+
+const (
+	DefaultErrorReportsLimit = 50
+	MaxErrorReportsLimit     = 500
+)
+
+type IErrorReportsUsecase interface {
+	Add(ctx context.Context, report model.ErrorReport) error
+	List(ctx context.Context, filter model.ErrorReportFilter) ([]model.ErrorReport, error)
+}
+
+type ErrorReportsUsecase struct {
+	IErrorReportsRepo repository.IErrorReportsRepository
+}
+
+func (eu *ErrorReportsUsecase) Add(ctx context.Context, report model.ErrorReport) error {
+	return eu.IErrorReportsRepo.Add(ctx, report)
+}
+
+func (eu *ErrorReportsUsecase) List(ctx context.Context, filter model.ErrorReportFilter) ([]model.ErrorReport, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultErrorReportsLimit
+	}
+	if filter.Limit > MaxErrorReportsLimit {
+		filter.Limit = MaxErrorReportsLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return eu.IErrorReportsRepo.List(ctx, filter)
+}
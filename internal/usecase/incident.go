@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"time"
+)
+
+var ErrIncidentNotFound = errors.New("incident not found")
+
+type IIncidentsUsecase interface {
+	List(ctx context.Context, filter model.IncidentFilter, roleID, userId int) ([]model.Incident, error)
+	Timeline(ctx context.Context, id int64, roleID, userId int) (model.IncidentTimeline, error)
+	Acknowledge(ctx context.Context, id int64, message, username string, roleID, userId int) error
+	Comment(ctx context.Context, id int64, message, username string, roleID, userId int) error
+}
+
+type IncidentsUsecase struct {
+	IIncidentsRepo   repository.IIncidentsRepository
+	IUserServiceRepo repository.IUserServiceRepo
+}
+
+// List returns incidents, callers that can't read every service only see
+// the incidents of the services assigned to them.
+func (iu *IncidentsUsecase) List(ctx context.Context, filter model.IncidentFilter, roleID, userId int) ([]model.Incident, error) {
+	if !canReadAllServices(ctx, roleID) {
+		ids, err := iu.assignedServices(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		filter.ServiceIDs = ids
+	}
+	if filter.Limit <= 0 || filter.Limit > MaxErrorReportsLimit {
+		filter.Limit = DefaultErrorReportsLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return iu.IIncidentsRepo.List(ctx, filter)
+}
+
+func (iu *IncidentsUsecase) Timeline(ctx context.Context, id int64, roleID, userId int) (timeline model.IncidentTimeline, err error) {
+	timeline.Incident, err = iu.get(ctx, id, roleID, userId)
+	if err != nil {
+		return model.IncidentTimeline{}, err
+	}
+	timeline.Events, err = iu.IIncidentsRepo.Events(ctx, id)
+	if err != nil {
+		return model.IncidentTimeline{}, err
+	}
+	timeline.ErrorReports, err = iu.IIncidentsRepo.ErrorReports(ctx, id)
+	if err != nil {
+		return model.IncidentTimeline{}, err
+	}
+	return timeline, nil
+}
+
+func (iu *IncidentsUsecase) Acknowledge(ctx context.Context, id int64, message, username string, roleID, userId int) error {
+	incident, err := iu.get(ctx, id, roleID, userId)
+	if err != nil {
+		return err
+	}
+	if incident.Status != model.IncidentOpen {
+		return errors.New("only open incidents can be acknowledged")
+	}
+
+	now := time.Now()
+	if err := iu.IIncidentsRepo.Acknowledge(ctx, id, username, now); err != nil {
+		return err
+	}
+	return iu.IIncidentsRepo.AddEvent(ctx, model.IncidentEvent{
+		IncidentID: id,
+		Kind:       model.EventAcknowledged,
+		Author:     username,
+		Message:    message,
+		CreatedAt:  now,
+	})
+}
+
+func (iu *IncidentsUsecase) Comment(ctx context.Context, id int64, message, username string, roleID, userId int) error {
+	if message == "" {
+		return errors.New("message must be filled")
+	}
+	if _, err := iu.get(ctx, id, roleID, userId); err != nil {
+		return err
+	}
+	return iu.IIncidentsRepo.AddEvent(ctx, model.IncidentEvent{
+		IncidentID: id,
+		Kind:       model.EventComment,
+		Author:     username,
+		Message:    message,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// get returns the incident if the user may see it.
+func (iu *IncidentsUsecase) get(ctx context.Context, id int64, roleID, userId int) (model.Incident, error) {
+	incident, err := iu.IIncidentsRepo.Get(ctx, id)
+	if err != nil {
+		return model.Incident{}, err
+	}
+	if incident.ID == 0 {
+		return model.Incident{}, ErrIncidentNotFound
+	}
+	if canReadAllServices(ctx, roleID) {
+		return incident, nil
+	}
+
+	ids, err := iu.assignedServices(ctx, userId)
+	if err != nil {
+		return model.Incident{}, err
+	}
+	for _, serviceID := range ids {
+		if serviceID == incident.ServiceID {
+			return incident, nil
+		}
+	}
+	return model.Incident{}, ErrIncidentNotFound
+}
+
+func (iu *IncidentsUsecase) assignedServices(ctx context.Context, userId int) ([]int, error) {
+	ids, err := iu.IUserServiceRepo.AccessibleServices(ctx, userId)
+	if ids == nil && err == nil {
+		// an empty, not a nil, restriction
+		ids = []int{}
+	}
+	return ids, err
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"reflect"
+	"testing"
+)
+
+type fakeIncidents struct {
+	repository.IIncidentsRepository
+	incidents map[int64]model.Incident
+	filter    model.IncidentFilter
+}
+
+func (f *fakeIncidents) Get(ctx context.Context, id int64) (model.Incident, error) {
+	return f.incidents[id], nil
+}
+
+func (f *fakeIncidents) List(ctx context.Context, filter model.IncidentFilter) ([]model.Incident, error) {
+	f.filter = filter
+	return nil, nil
+}
+
+type fakeAssignments struct {
+	repository.IUserServiceRepo
+	services map[int][]int
+}
+
+func (f *fakeAssignments) AccessibleServices(ctx context.Context, userId int) ([]int, error) {
+	return f.services[userId], nil
+}
+
+func TestIncidentsFollowPermissions(t *testing.T) {
+	admin := model.NewPrincipal(1, "admin", model.Admin)
+	// an admin's API key scoped to the services it reads
+	scoped := model.NewPrincipal(1, "admin", model.Admin)
+	scoped.Permissions = []model.Permission{model.PermServiceRead}
+	regular := model.NewPrincipal(2, "user", model.Regular)
+
+	tests := []struct {
+		name      string
+		principal *model.Principal
+		role      model.AccessLevel
+		services  []int // nil when unrestricted
+	}{
+		{name: "admin", principal: admin, role: model.Admin},
+		{name: "scoped api key", principal: scoped, role: model.Admin, services: []int{10}},
+		{name: "regular", principal: regular, role: model.Regular, services: []int{20}},
+		{name: "no principal, admin role", role: model.Admin},
+		{name: "no principal, regular role", role: model.Regular, services: []int{20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incidents := &fakeIncidents{incidents: map[int64]model.Incident{
+				1: {ID: 1, ServiceID: 10},
+				2: {ID: 2, ServiceID: 20},
+			}}
+			iu := &IncidentsUsecase{
+				IIncidentsRepo:   incidents,
+				IUserServiceRepo: &fakeAssignments{services: map[int][]int{1: {10}, 2: {20}}},
+			}
+			ctx, userID := context.Background(), 2
+			if tt.principal != nil {
+				ctx = model.ContextWithPrincipal(ctx, tt.principal)
+				userID = tt.principal.UserID
+			}
+
+			if _, err := iu.List(ctx, model.IncidentFilter{}, int(tt.role), userID); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(incidents.filter.ServiceIDs, tt.services) {
+				t.Errorf("listed services %v, want %v", incidents.filter.ServiceIDs, tt.services)
+			}
+
+			for id, incident := range incidents.incidents {
+				_, err := iu.get(ctx, id, int(tt.role), userID)
+				visible := tt.services == nil
+				for _, serviceID := range tt.services {
+					visible = visible || serviceID == incident.ServiceID
+				}
+				if visible && err != nil {
+					t.Errorf("incident %d: %v", id, err)
+				}
+				if !visible && !errors.Is(err, ErrIncidentNotFound) {
+					t.Errorf("incident %d: got %v, expected it to be hidden", id, err)
+				}
+			}
+		})
+	}
+}
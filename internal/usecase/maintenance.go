@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+)
+
+type IMaintenanceUsecase interface {
+	Add(ctx context.Context, window model.MaintenanceWindow) error
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context) ([]model.MaintenanceWindow, error)
+}
+
+type MaintenanceUsecase struct {
+	IMaintenanceRepo repository.IMaintenanceRepository
+	IServicesRepo    repository.IServicesRepository
+}
+
+// Add creates a window for the service named window.ServiceName, or for
+// every service when it's empty.
+func (mu *MaintenanceUsecase) Add(ctx context.Context, window model.MaintenanceWindow) error {
+	if window.Title == "" {
+		return errors.New("title must be filled")
+	}
+	if !window.EndsAt.After(window.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	switch window.Recurrence {
+	case model.RecurrenceNone, model.RecurrenceDaily, model.RecurrenceWeekly:
+	default:
+		return errors.New("recurrence must be empty, daily or weekly")
+	}
+
+	window.ServiceID = nil
+	if window.ServiceName != "" {
+		service, err := mu.IServicesRepo.GetByName(ctx, window.ServiceName)
+		if err != nil {
+			return err
+		}
+		if service.ID == 0 {
+			return ErrServiceNotFound
+		}
+		window.ServiceID = &service.ID
+	}
+
+	return mu.IMaintenanceRepo.Add(ctx, window)
+}
+
+func (mu *MaintenanceUsecase) Delete(ctx context.Context, id int) error {
+	return mu.IMaintenanceRepo.Delete(ctx, id)
+}
+
+func (mu *MaintenanceUsecase) List(ctx context.Context) ([]model.MaintenanceWindow, error) {
+	return mu.IMaintenanceRepo.List(ctx)
+}
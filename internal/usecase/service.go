@@ -2,10 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"monitoring/internal/model"
 	"monitoring/internal/repository"
 )
 
+var ErrServiceNotFound = errors.New("service not found")
+
 type IServicesUsecase interface {
 	Add(ctx context.Context, service model.Service, userIds []int) error
 	GetUserService(ctx context.Context, serviceName string, roleID, userId int) (service model.Service, err error)
@@ -17,10 +20,25 @@ type IServicesUsecase interface {
 
 type ServicesUsecase struct {
 	IServicesRepo repository.IServicesRepository
+	// records changes when set
+	IAuditUC IAuditUsecase
 }
 
 func (su *ServicesUsecase) Add(ctx context.Context, service model.Service, userIds []int) error {
-	return su.IServicesRepo.Add(ctx, service, userIds)
+	if err := su.IServicesRepo.Add(ctx, service, userIds); err != nil {
+		return err
+	}
+	added, err := su.IServicesRepo.GetByName(ctx, serviceName(service))
+	if err != nil {
+		AuditFailed(err, model.AuditCreate, model.AuditTargetService, serviceName(service))
+		return nil
+	}
+	after := struct {
+		model.Service
+		UserIDs []int `json:"user_ids,omitempty"`
+	}{auditService(added), userIds}
+	RecordAudit(ctx, su.IAuditUC, model.AuditCreate, model.AuditTargetService, serviceName(added), nil, after)
+	return nil
 }
 
 func (su *ServicesUsecase) GetUserService(ctx context.Context, serviceName string, roleID, userId int) (service model.Service, err error) {
@@ -39,10 +57,90 @@ func (su *ServicesUsecase) List(ctx context.Context) ([]model.Service, error) {
 	return su.IServicesRepo.List(ctx)
 }
 
+// Update changes the service named service.Name, or the one with
+// service.ID when the name is empty.
 func (su *ServicesUsecase) Update(ctx context.Context, service model.Service) error {
-	return su.IServicesRepo.Update(ctx, service)
+	before, err := su.find(ctx, service)
+	if err != nil {
+		return err
+	}
+	if before.ID == 0 {
+		return ErrServiceNotFound
+	}
+	if err := su.IServicesRepo.Update(ctx, service); err != nil {
+		return err
+	}
+	after, err := su.find(ctx, model.Service{ID: before.ID})
+	if err != nil {
+		AuditFailed(err, model.AuditUpdate, model.AuditTargetService, serviceName(before))
+		return nil
+	}
+	RecordAudit(ctx, su.IAuditUC, model.AuditUpdate, model.AuditTargetService, serviceName(after),
+		auditService(before), auditService(after))
+	return nil
 }
 
 func (su *ServicesUsecase) Delete(ctx context.Context, service model.Service) error {
-	return su.IServicesRepo.Delete(ctx, service)
+	before, err := su.IServicesRepo.GetByName(ctx, serviceName(service))
+	if err != nil {
+		return err
+	}
+	if err := su.IServicesRepo.Delete(ctx, service); err != nil {
+		return err
+	}
+	if before.ID != 0 {
+		RecordAudit(ctx, su.IAuditUC, model.AuditDelete, model.AuditTargetService, serviceName(before),
+			auditService(before), nil)
+	}
+	return nil
+}
+
+// find looks a service up by name, or by id when the name is empty.
+func (su *ServicesUsecase) find(ctx context.Context, service model.Service) (model.Service, error) {
+	if name := serviceName(service); name != "" {
+		return su.IServicesRepo.GetByName(ctx, name)
+	}
+	return su.IServicesRepo.GetByID(ctx, service.ID)
+}
+
+func serviceName(service model.Service) string {
+	if service.Name == nil {
+		return ""
+	}
+	return *service.Name
+}
+
+// auditService drops the fields the checks keep changing from a service.
+func auditService(service model.Service) model.Service {
+	service.ErrorEstimate = 0
+	service.ExecutionTime = nil
+	return service
+}
+
+// canReadAllServices reports whether the caller sees every service rather
+// than only the ones assigned to them. The principal of ctx decides, callers
+// without one get the permissions of roleID.
+func canReadAllServices(ctx context.Context, roleID int) bool {
+	principal := model.PrincipalFromContext(ctx)
+	if principal == nil {
+		principal = model.NewPrincipal(0, "", model.AccessLevel(roleID))
+	}
+	return principal.Can(model.PermServiceReadAll)
+}
+
+// accessibleService looks a service up by name, callers that may read every
+// service see it while others only see the ones assigned to them.
+func accessibleService(ctx context.Context, repo repository.IServicesRepository, serviceName string, roleID, userId int) (service model.Service, err error) {
+	if canReadAllServices(ctx, roleID) {
+		service, err = repo.GetByName(ctx, serviceName)
+	} else {
+		service, err = repo.GetUserService(ctx, serviceName, userId, roleID)
+	}
+	if err != nil {
+		return model.Service{}, err
+	}
+	if service.ID == 0 {
+		return model.Service{}, ErrServiceNotFound
+	}
+	return service, nil
 }
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"time"
+)
+
+const (
+	DefaultHistogramBuckets = 10
+	MaxHistogramBuckets     = 100
+)
+
+type IStatisticsUsecase interface {
+	GetLatencyStats(ctx context.Context, serviceName string, roleID, userId int, from, to time.Time, buckets int) (model.LatencyStats, error)
+}
+
+type StatisticsUsecase struct {
+	IServicesRepo      repository.IServicesRepository
+	IServiceChecksRepo repository.IServiceChecksRepository
+}
+
+func (su *StatisticsUsecase) GetLatencyStats(ctx context.Context, serviceName string, roleID, userId int, from, to time.Time, buckets int) (model.LatencyStats, error) {
+	service, err := accessibleService(ctx, su.IServicesRepo, serviceName, roleID, userId)
+	if err != nil {
+		return model.LatencyStats{}, err
+	}
+
+	if buckets <= 0 {
+		buckets = DefaultHistogramBuckets
+	}
+	if buckets > MaxHistogramBuckets {
+		buckets = MaxHistogramBuckets
+	}
+
+	stats, err := su.IServiceChecksRepo.LatencyStats(ctx, service.ID, from, to, buckets)
+	if err != nil {
+		return model.LatencyStats{}, err
+	}
+	stats.ServiceName = serviceName
+	return stats, nil
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"time"
+)
+
+// StatusPageDays is the number of days shown as uptime bars.
+const StatusPageDays = 90
+
+type IStatusPageUsecase interface {
+	Get(ctx context.Context) (model.StatusPage, error)
+}
+
+type StatusPageUsecase struct {
+	Title              string
+	IServicesRepo      repository.IServicesRepository
+	IServiceChecksRepo repository.IServiceChecksRepository
+	IIncidentsRepo     repository.IIncidentsRepository
+	IMaintenanceRepo   repository.IMaintenanceRepository
+}
+
+// Get builds the public status page from the services opted in to it. Only
+// what is safe to show anonymously is included.
+func (su *StatusPageUsecase) Get(ctx context.Context) (model.StatusPage, error) {
+	now := time.Now().UTC()
+	page := model.StatusPage{
+		Title:       su.Title,
+		GeneratedAt: now,
+		Services:    []model.ServiceStatus{},
+		Incidents:   []model.Incident{},
+	}
+
+	services, err := su.IServicesRepo.ListPublic(ctx)
+	if err != nil {
+		return model.StatusPage{}, err
+	}
+	if len(services) == 0 {
+		return page, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, -(StatusPageDays - 1))
+	to := today.AddDate(0, 0, 1)
+
+	serviceIDs := make([]int, 0, len(services))
+	for _, service := range services {
+		serviceIDs = append(serviceIDs, service.ID)
+
+		status, err := su.serviceStatus(ctx, service, from, to)
+		if err != nil {
+			return model.StatusPage{}, err
+		}
+		page.Services = append(page.Services, status)
+	}
+
+	incidents, err := su.IIncidentsRepo.List(ctx, model.IncidentFilter{Unresolved: true, ServiceIDs: serviceIDs})
+	if err != nil {
+		return model.StatusPage{}, err
+	}
+	for _, incident := range incidents {
+		page.Incidents = append(page.Incidents, model.Incident{
+			ServiceName:    incident.ServiceName,
+			Status:         incident.Status,
+			Title:          incident.Title,
+			OpenedAt:       incident.OpenedAt,
+			AcknowledgedAt: incident.AcknowledgedAt,
+		})
+	}
+
+	return page, nil
+}
+
+func (su *StatusPageUsecase) serviceStatus(ctx context.Context, service model.Service, from, to time.Time) (model.ServiceStatus, error) {
+	status := model.ServiceStatus{Uptime: 100}
+	if service.Name != nil {
+		status.Name = *service.Name
+	}
+
+	latest, err := su.IServiceChecksRepo.Latest(ctx, service.ID, 1)
+	if err != nil {
+		return model.ServiceStatus{}, err
+	}
+	if len(latest) > 0 {
+		status.Up = latest[0].Success
+		status.LastCheckedAt = &latest[0].CheckedAt
+	}
+
+	checks, err := su.IServiceChecksRepo.List(ctx, service.ID, from, to)
+	if err != nil {
+		return model.ServiceStatus{}, err
+	}
+	// maintenance doesn't count, the same as for the uptime in the panel
+	var excluded []model.Interval
+	if su.IMaintenanceRepo != nil {
+		windows, err := su.IMaintenanceRepo.ListForService(ctx, service.ID)
+		if err != nil {
+			return model.ServiceStatus{}, err
+		}
+		for _, window := range windows {
+			excluded = append(excluded, window.Occurrences(from, to)...)
+		}
+	}
+	if uptime := ComputeUptime(checks, from, to, excluded); uptime.Availability != nil {
+		status.Uptime = *uptime.Availability
+	}
+
+	// one entry per day, days without checks stay empty
+	i := 0
+	for date := from; date.Before(to); date = date.AddDate(0, 0, 1) {
+		next := date.AddDate(0, 0, 1)
+		day := model.DailyUptime{Date: date, Availability: 100}
+
+		var dayChecks []model.CheckResult
+		if i > 0 {
+			// the last check of the day before holds until the first of this one
+			carried := checks[i-1]
+			carried.CheckedAt = date
+			dayChecks = append(dayChecks, carried)
+		}
+		for ; i < len(checks) && checks[i].CheckedAt.Before(next); i++ {
+			dayChecks = append(dayChecks, checks[i])
+			day.Checks++
+		}
+		if day.Checks == 0 {
+			day.Availability = 0
+		} else if uptime := ComputeUptime(dayChecks, date, next, excluded); uptime.Availability != nil {
+			day.Availability = *uptime.Availability
+		}
+		status.Days = append(status.Days, day)
+	}
+
+	return status, nil
+}
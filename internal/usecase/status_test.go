@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"testing"
+	"time"
+)
+
+type fakeStatusChecks struct {
+	repository.IServiceChecksRepository
+	checks []model.CheckResult
+}
+
+func (f *fakeStatusChecks) List(ctx context.Context, serviceID int, from, to time.Time) (checks []model.CheckResult, err error) {
+	for _, check := range f.checks {
+		if !check.CheckedAt.Before(from) && check.CheckedAt.Before(to) {
+			checks = append(checks, check)
+		}
+	}
+	return checks, nil
+}
+
+func (f *fakeStatusChecks) Latest(ctx context.Context, serviceID int, n int) ([]model.CheckResult, error) {
+	if len(f.checks) == 0 {
+		return nil, nil
+	}
+	return f.checks[len(f.checks)-1:], nil
+}
+
+type fakeMaintenance struct {
+	repository.IMaintenanceRepository
+	windows []model.MaintenanceWindow
+}
+
+func (f *fakeMaintenance) ListForService(ctx context.Context, serviceID int) ([]model.MaintenanceWindow, error) {
+	return f.windows, nil
+}
+
+func TestStatusPageExcludesMaintenance(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, -(StatusPageDays - 1))
+	to := today.AddDate(0, 0, 1)
+	yesterday := today.AddDate(0, 0, -1)
+
+	// up the day before yesterday, down from 06:00 to 12:00 yesterday during
+	// maintenance, then up
+	checks := &fakeStatusChecks{checks: []model.CheckResult{
+		{CheckedAt: yesterday.AddDate(0, 0, -1), Success: true},
+		{CheckedAt: yesterday.Add(6 * time.Hour), Success: false},
+		{CheckedAt: yesterday.Add(12 * time.Hour), Success: true},
+	}}
+	su := &StatusPageUsecase{
+		IServiceChecksRepo: checks,
+		IMaintenanceRepo: &fakeMaintenance{windows: []model.MaintenanceWindow{
+			{StartsAt: yesterday.Add(6 * time.Hour), EndsAt: yesterday.Add(12 * time.Hour)},
+		}},
+	}
+	name := "api"
+
+	status, err := su.serviceStatus(context.Background(), model.Service{ID: 1, Name: &name}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Uptime != 100 {
+		t.Errorf("uptime = %v, maintenance shouldn't count", status.Uptime)
+	}
+	if len(status.Days) != StatusPageDays {
+		t.Fatalf("%d days, want %d", len(status.Days), StatusPageDays)
+	}
+	day := status.Days[len(status.Days)-2]
+	if !day.Date.Equal(yesterday) || day.Checks != 2 || day.Availability != 100 {
+		t.Errorf("yesterday = %+v, want 2 checks at 100%%", day)
+	}
+	if today := status.Days[len(status.Days)-1]; today.Checks != 0 {
+		t.Errorf("today = %+v, want no checks", today)
+	}
+
+	// without the window the outage counts, a quarter of yesterday
+	su.IMaintenanceRepo = &fakeMaintenance{}
+	status, err = su.serviceStatus(context.Background(), model.Service{ID: 1, Name: &name}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if day := status.Days[len(status.Days)-2]; day.Availability != 75 {
+		t.Errorf("yesterday = %+v, want 75%%", day)
+	}
+	if status.Uptime >= 100 {
+		t.Errorf("uptime = %v, the outage should count", status.Uptime)
+	}
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/stream"
+)
+
+var ErrStreamAccessRevoked = errors.New("access to the stream was revoked")
+
+type IStreamUsecase interface {
+	Subscribe(ctx context.Context, principal *model.Principal) (*stream.Subscription, error)
+	Refresh(ctx context.Context, sub *stream.Subscription, principal *model.Principal) error
+	Unsubscribe(sub *stream.Subscription)
+}
+
+type StreamUsecase struct {
+	Hub              *stream.Hub
+	IUserServiceRepo repository.IUserServiceRepo
+	IUserRepo        userrepo.IUserRepo
+}
+
+// Subscribe returns a subscription to live check results, users other than
+// admins only receive the services they may access.
+func (su *StreamUsecase) Subscribe(ctx context.Context, principal *model.Principal) (*stream.Subscription, error) {
+	filter, err := su.filter(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+	return su.Hub.Subscribe(filter), nil
+}
+
+// Refresh re-resolves the services the subscriber may access, as they may
+// have changed since it subscribed. It returns ErrStreamAccessRevoked when
+// the user was deleted, its role changed or it lost access to the stream.
+func (su *StreamUsecase) Refresh(ctx context.Context, sub *stream.Subscription, principal *model.Principal) error {
+	user, err := su.IUserRepo.Read(ctx, principal.Name)
+	if err != nil {
+		return err
+	}
+	if user.UserId != principal.UserID || model.AccessLevel(user.Role) != principal.Role ||
+		!principal.Can(model.PermServiceRead) {
+		return ErrStreamAccessRevoked
+	}
+
+	filter, err := su.filter(ctx, principal)
+	if err != nil {
+		return err
+	}
+	su.Hub.SetFilter(sub, filter)
+	return nil
+}
+
+func (su *StreamUsecase) Unsubscribe(sub *stream.Subscription) {
+	su.Hub.Unsubscribe(sub)
+}
+
+// filter accepts the services principal may access, every one when it may
+// read them all.
+func (su *StreamUsecase) filter(ctx context.Context, principal *model.Principal) (func(serviceID int) bool, error) {
+	if principal.Can(model.PermServiceReadAll) {
+		return nil, nil
+	}
+
+	serviceIDs, err := su.IUserServiceRepo.AccessibleServices(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	assigned := make(map[int]bool, len(serviceIDs))
+	for _, serviceID := range serviceIDs {
+		assigned[serviceID] = true
+	}
+
+	return func(serviceID int) bool {
+		return assigned[serviceID]
+	}, nil
+}
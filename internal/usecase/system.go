@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"monitoring/internal/repository/userrepo"
+	"time"
+)
+
+// SystemErrorReportsWindow is how far back error reports are included in a
+// system snapshot.
+const SystemErrorReportsWindow = 24 * time.Hour
+
+type ISystemUsecase interface {
+	Get(ctx context.Context) (model.System, error)
+	GetUserSystem(ctx context.Context, username string, roleID, userId int) (model.System, error)
+}
+
+type SystemUsecase struct {
+	IServicesRepo     repository.IServicesRepository
+	IUserRepo         userrepo.IUserRepo
+	IErrorReportsRepo repository.IErrorReportsRepository
+}
+
+// Get returns every service, user and the recent error reports.
+func (su *SystemUsecase) Get(ctx context.Context) (system model.System, err error) {
+	system.Services, err = su.IServicesRepo.List(ctx)
+	if err != nil {
+		return model.System{}, err
+	}
+
+	users, err := su.IUserRepo.ReadAll(ctx)
+	if err != nil {
+		return model.System{}, err
+	}
+	for _, user := range users {
+		system.Users = append(system.Users, model.User{
+			Username:    user.Username,
+			AccessLevel: model.AccessLevel(user.Role),
+		})
+	}
+
+	system.ErrorReports, err = su.IErrorReportsRepo.List(ctx, model.ErrorReportFilter{
+		From:  time.Now().Add(-SystemErrorReportsWindow),
+		Limit: MaxErrorReportsLimit,
+	})
+	if err != nil {
+		return model.System{}, err
+	}
+
+	return system, nil
+}
+
+// GetUserSystem returns the snapshot restricted to the services assigned to
+// the user, directly or through their teams.
+func (su *SystemUsecase) GetUserSystem(ctx context.Context, username string, roleID, userId int) (system model.System, err error) {
+	system.Services, err = su.IServicesRepo.GetUserServices(ctx, roleID, userId)
+	if err != nil {
+		return model.System{}, err
+	}
+
+	system.Users = []model.User{{
+		Username:    username,
+		AccessLevel: model.AccessLevel(roleID),
+	}}
+
+	if len(system.Services) == 0 {
+		return system, nil
+	}
+
+	names := make([]string, 0, len(system.Services))
+	for _, service := range system.Services {
+		if service.Name != nil {
+			names = append(names, *service.Name)
+		}
+	}
+	system.ErrorReports, err = su.IErrorReportsRepo.List(ctx, model.ErrorReportFilter{
+		ServiceNames: names,
+		From:         time.Now().Add(-SystemErrorReportsWindow),
+		Limit:        MaxErrorReportsLimit,
+	})
+	if err != nil {
+		return model.System{}, err
+	}
+
+	return system, nil
+}
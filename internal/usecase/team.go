@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+)
+
+var ErrTeamNotFound = errors.New("team not found")
+
+type ITeamsUsecase interface {
+	List(ctx context.Context) ([]model.Team, error)
+	Get(ctx context.Context, id int) (model.Team, error)
+	Add(ctx context.Context, team model.Team) error
+	Update(ctx context.Context, team model.Team) error
+	Delete(ctx context.Context, id int) error
+	AddMember(ctx context.Context, member model.TeamMember) error
+	RemoveMember(ctx context.Context, member model.TeamMember) error
+	AddService(ctx context.Context, teamService model.TeamService) error
+	RemoveService(ctx context.Context, teamService model.TeamService) error
+}
+
+type TeamsUsecase struct {
+	ITeamsRepo repository.ITeamsRepository
+	// records changes when set, membership and services are recorded as
+	// updates of the team as they grant access
+	IAuditUC IAuditUsecase
+}
+
+func (tu *TeamsUsecase) List(ctx context.Context) ([]model.Team, error) {
+	return tu.ITeamsRepo.List(ctx)
+}
+
+func (tu *TeamsUsecase) Get(ctx context.Context, id int) (model.Team, error) {
+	team, found, err := tu.ITeamsRepo.Get(ctx, id)
+	if err != nil {
+		return model.Team{}, err
+	}
+	if !found {
+		return model.Team{}, ErrTeamNotFound
+	}
+	return team, nil
+}
+
+func (tu *TeamsUsecase) Add(ctx context.Context, team model.Team) error {
+	if team.Name == "" {
+		return errors.New("team name must be filled")
+	}
+	if err := tu.ITeamsRepo.Add(ctx, team); err != nil {
+		return err
+	}
+	RecordAudit(ctx, tu.IAuditUC, model.AuditCreate, model.AuditTargetTeam, team.Name, nil,
+		model.Team{Name: team.Name, Description: team.Description})
+	return nil
+}
+
+func (tu *TeamsUsecase) Update(ctx context.Context, team model.Team) error {
+	if team.Name == "" {
+		return errors.New("team name must be filled")
+	}
+	return tu.change(ctx, team.ID, func() error {
+		return tu.ITeamsRepo.Update(ctx, team)
+	})
+}
+
+func (tu *TeamsUsecase) Delete(ctx context.Context, id int) error {
+	before, found, err := tu.ITeamsRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := tu.ITeamsRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if found {
+		RecordAudit(ctx, tu.IAuditUC, model.AuditDelete, model.AuditTargetTeam, before.Name, before, nil)
+	}
+	return nil
+}
+
+func (tu *TeamsUsecase) AddMember(ctx context.Context, member model.TeamMember) error {
+	return tu.change(ctx, member.TeamID, func() error {
+		return tu.ITeamsRepo.AddMember(ctx, member)
+	})
+}
+
+func (tu *TeamsUsecase) RemoveMember(ctx context.Context, member model.TeamMember) error {
+	err := tu.change(ctx, member.TeamID, func() error {
+		return tu.ITeamsRepo.RemoveMember(ctx, member)
+	})
+	if errors.Is(err, ErrTeamNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (tu *TeamsUsecase) AddService(ctx context.Context, teamService model.TeamService) error {
+	return tu.change(ctx, teamService.TeamID, func() error {
+		return tu.ITeamsRepo.AddService(ctx, teamService)
+	})
+}
+
+func (tu *TeamsUsecase) RemoveService(ctx context.Context, teamService model.TeamService) error {
+	err := tu.change(ctx, teamService.TeamID, func() error {
+		return tu.ITeamsRepo.RemoveService(ctx, teamService)
+	})
+	if errors.Is(err, ErrTeamNotFound) {
+		return nil
+	}
+	return err
+}
+
+// change applies apply to the team with id and records it as an update of
+// the team, changes that leave the team as it was aren't recorded.
+func (tu *TeamsUsecase) change(ctx context.Context, id int, apply func() error) error {
+	before, err := tu.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+	after, err := tu.Get(ctx, id)
+	if err != nil {
+		AuditFailed(err, model.AuditUpdate, model.AuditTargetTeam, before.Name)
+		return nil
+	}
+	RecordAudit(ctx, tu.IAuditUC, model.AuditUpdate, model.AuditTargetTeam, after.Name, before, after)
+	return nil
+}
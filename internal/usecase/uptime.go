@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"monitoring/internal/model"
+	"monitoring/internal/repository"
+	"sort"
+	"time"
+)
+
+type IUptimeUsecase interface {
+	GetUptime(ctx context.Context, serviceName string, roleID, userId int, from, to time.Time) (model.Uptime, error)
+}
+
+type UptimeUsecase struct {
+	IServicesRepo      repository.IServicesRepository
+	IServiceChecksRepo repository.IServiceChecksRepository
+	IMaintenanceRepo   repository.IMaintenanceRepository
+}
+
+func (uu *UptimeUsecase) GetUptime(ctx context.Context, serviceName string, roleID, userId int, from, to time.Time) (model.Uptime, error) {
+	service, err := accessibleService(ctx, uu.IServicesRepo, serviceName, roleID, userId)
+	if err != nil {
+		return model.Uptime{}, err
+	}
+
+	checks, err := uu.IServiceChecksRepo.List(ctx, service.ID, from, to)
+	if err != nil {
+		return model.Uptime{}, err
+	}
+
+	var excluded []model.Interval
+	if uu.IMaintenanceRepo != nil {
+		windows, err := uu.IMaintenanceRepo.ListForService(ctx, service.ID)
+		if err != nil {
+			return model.Uptime{}, err
+		}
+		for _, window := range windows {
+			excluded = append(excluded, window.Occurrences(from, to)...)
+		}
+	}
+
+	uptime := ComputeUptime(checks, from, to, excluded)
+	uptime.ServiceName = serviceName
+	return uptime, nil
+}
+
+// ComputeUptime derives availability from checks ordered by time. Every
+// check is assumed to hold until the next one (or until to, capped at now),
+// the time before the first check in the window and the excluded intervals
+// (maintenance) aren't counted. Availability is nil when no check covers
+// the window, rather than reporting an outage.
+func ComputeUptime(checks []model.CheckResult, from, to time.Time, excluded []model.Interval) model.Uptime {
+	uptime := model.Uptime{From: from, To: to, Checks: len(checks)}
+
+	end := to
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+
+	var up, down time.Duration
+	wasUp := true
+	for i, check := range checks {
+		next := end
+		if i+1 < len(checks) {
+			next = checks[i+1].CheckedAt
+		}
+		d := next.Sub(check.CheckedAt) - overlap(check.CheckedAt, next, excluded)
+		if d <= 0 {
+			continue
+		}
+
+		if check.Success {
+			up += d
+		} else {
+			down += d
+			if wasUp {
+				uptime.Outages++
+			}
+		}
+		wasUp = check.Success
+	}
+
+	if total := up + down; total > 0 {
+		availability := float64(up) / float64(total) * 100
+		uptime.Availability = &availability
+	}
+	if uptime.Outages > 0 {
+		uptime.MTTR = down.Seconds() / float64(uptime.Outages)
+		uptime.MTBF = up.Seconds() / float64(uptime.Outages)
+	}
+
+	return uptime
+}
+
+// overlap returns how much of [start, end) is covered by intervals, which
+// may overlap each other.
+func overlap(start, end time.Time, intervals []model.Interval) (covered time.Duration) {
+	var clipped []model.Interval
+	for _, i := range intervals {
+		if i.Start.Before(start) {
+			i.Start = start
+		}
+		if i.End.After(end) {
+			i.End = end
+		}
+		if i.End.After(i.Start) {
+			clipped = append(clipped, i)
+		}
+	}
+	sort.Slice(clipped, func(a, b int) bool { return clipped[a].Start.Before(clipped[b].Start) })
+
+	var last time.Time
+	for _, i := range clipped {
+		if i.Start.Before(last) {
+			i.Start = last
+		}
+		if i.End.After(i.Start) {
+			covered += i.End.Sub(i.Start)
+			last = i.End
+		}
+	}
+	return covered
+}
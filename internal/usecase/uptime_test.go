@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"math"
+	"monitoring/internal/model"
+	"testing"
+	"time"
+)
+
+var base = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func minutes(m int) time.Time {
+	return base.Add(time.Duration(m) * time.Minute)
+}
+
+func interval(from, to int) model.Interval {
+	return model.Interval{Start: minutes(from), End: minutes(to)}
+}
+
+func check(m int, success bool) model.CheckResult {
+	return model.CheckResult{CheckedAt: minutes(m), Success: success}
+}
+
+func TestOverlap(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []model.Interval
+		want      time.Duration
+	}{
+		{name: "none"},
+		{
+			name:      "inside",
+			intervals: []model.Interval{interval(10, 20)},
+			want:      10 * time.Minute,
+		},
+		{
+			name:      "clipped on both ends",
+			intervals: []model.Interval{interval(-10, 100)},
+			want:      60 * time.Minute,
+		},
+		{
+			name:      "outside",
+			intervals: []model.Interval{interval(-20, -10), interval(60, 70)},
+		},
+		{
+			name:      "overlapping each other",
+			intervals: []model.Interval{interval(10, 30), interval(20, 40)},
+			want:      30 * time.Minute,
+		},
+		{
+			name:      "nested",
+			intervals: []model.Interval{interval(10, 40), interval(20, 30), interval(35, 50)},
+			want:      40 * time.Minute,
+		},
+		{
+			name:      "unordered",
+			intervals: []model.Interval{interval(40, 50), interval(0, 5)},
+			want:      15 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := overlap(minutes(0), minutes(60), tt.intervals); got != tt.want {
+			t.Errorf("%s: overlap = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestComputeUptime(t *testing.T) {
+	tests := []struct {
+		name         string
+		checks       []model.CheckResult
+		excluded     []model.Interval
+		availability float64 // -1 when nil
+		outages      int
+		mttr, mtbf   float64
+	}{
+		{
+			name:         "no checks",
+			availability: -1,
+		},
+		{
+			name:         "always up",
+			checks:       []model.CheckResult{check(0, true), check(30, true)},
+			availability: 100,
+		},
+		{
+			name:         "down a quarter",
+			checks:       []model.CheckResult{check(0, true), check(30, false), check(45, true)},
+			availability: 75,
+			outages:      1,
+			mttr:         15 * 60,
+			mtbf:         45 * 60,
+		},
+		{
+			name:         "time before the first check isn't counted",
+			checks:       []model.CheckResult{check(30, false), check(45, true)},
+			availability: 50,
+			outages:      1,
+			mttr:         15 * 60,
+			mtbf:         15 * 60,
+		},
+		{
+			name:         "consecutive failures are one outage",
+			checks:       []model.CheckResult{check(0, false), check(10, false), check(20, true), check(30, false), check(40, true)},
+			availability: 50,
+			outages:      2,
+			mttr:         15 * 60,
+			mtbf:         15 * 60,
+		},
+		{
+			name:         "maintenance is excluded",
+			checks:       []model.CheckResult{check(0, true), check(30, false), check(45, true)},
+			excluded:     []model.Interval{interval(30, 45)},
+			availability: 100,
+		},
+		{
+			name:         "partly in maintenance",
+			checks:       []model.CheckResult{check(0, true), check(20, false), check(40, true)},
+			excluded:     []model.Interval{interval(30, 40)},
+			availability: 80,
+			outages:      1,
+			mttr:         10 * 60,
+			mtbf:         40 * 60,
+		},
+		{
+			name:         "only maintenance",
+			checks:       []model.CheckResult{check(0, false)},
+			excluded:     []model.Interval{interval(0, 60)},
+			availability: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeUptime(tt.checks, minutes(0), minutes(60), tt.excluded)
+			if got.Checks != len(tt.checks) {
+				t.Errorf("checks = %d, want %d", got.Checks, len(tt.checks))
+			}
+			switch {
+			case tt.availability < 0 && got.Availability != nil:
+				t.Errorf("availability = %v, want none", *got.Availability)
+			case tt.availability >= 0 && got.Availability == nil:
+				t.Errorf("availability = nil, want %v", tt.availability)
+			case tt.availability >= 0 && math.Abs(*got.Availability-tt.availability) > 1e-9:
+				t.Errorf("availability = %v, want %v", *got.Availability, tt.availability)
+			}
+			if got.Outages != tt.outages || got.MTTR != tt.mttr || got.MTBF != tt.mtbf {
+				t.Errorf("outages, mttr, mtbf = %d, %v, %v, want %d, %v, %v",
+					got.Outages, got.MTTR, got.MTBF, tt.outages, tt.mttr, tt.mtbf)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"monitoring/internal/model"
 	"monitoring/internal/repository"
 )
@@ -20,10 +21,17 @@ type IUserService interface {
 
 type UserService struct {
 	UserServiceRepo repository.IUserServiceRepo
+	// records changes when set
+	IAuditUC IAuditUsecase
 }
 
 func (us *UserService) Add(ctx context.Context, userservice model.UserService) error {
-	return us.UserServiceRepo.Add(ctx, userservice)
+	if err := us.UserServiceRepo.Add(ctx, userservice); err != nil {
+		return err
+	}
+	RecordAudit(ctx, us.IAuditUC, model.AuditCreate, model.AuditTargetUserService, userServiceTarget(userservice),
+		nil, userservice)
+	return nil
 }
 
 func (us *UserService) GetUserService(ctx context.Context, userservice model.UserService) (model.UserService, error) {
@@ -31,7 +39,21 @@ func (us *UserService) GetUserService(ctx context.Context, userservice model.Use
 }
 
 func (us *UserService) DeleteUserService(ctx context.Context, userservice model.UserService) error {
-	return us.UserServiceRepo.DeleteUserService(ctx, userservice)
+	mappings, err := us.UserServiceRepo.GetServiceUsers(ctx, userservice)
+	if err != nil {
+		return err
+	}
+	if err := us.UserServiceRepo.DeleteUserService(ctx, userservice); err != nil {
+		return err
+	}
+	for _, mapping := range mappings {
+		if mapping.UserID == userservice.UserID {
+			RecordAudit(ctx, us.IAuditUC, model.AuditDelete, model.AuditTargetUserService, userServiceTarget(mapping),
+				mapping, nil)
+			return nil
+		}
+	}
+	return nil
 }
 
 func (us *UserService) GetServiceUsers(ctx context.Context, userservice model.UserService) ([]model.UserService, error) {
@@ -46,14 +68,53 @@ func (us *UserService) List(ctx context.Context) ([]model.UserService, error) {
 	return us.UserServiceRepo.List(ctx)
 }
 
+// Update assigns every mapping of the service to newUserService.UserID.
 func (us *UserService) Update(ctx context.Context, newUserService model.UserService) error {
-	return us.UserServiceRepo.Update(ctx, newUserService)
+	before, err := us.UserServiceRepo.GetServiceUsers(ctx, newUserService)
+	if err != nil {
+		return err
+	}
+	if err := us.UserServiceRepo.Update(ctx, newUserService); err != nil {
+		return err
+	}
+	after, err := us.UserServiceRepo.GetServiceUsers(ctx, newUserService)
+	if err != nil {
+		AuditFailed(err, model.AuditUpdate, model.AuditTargetUserService, userServiceTarget(newUserService))
+		return nil
+	}
+	RecordAudit(ctx, us.IAuditUC, model.AuditUpdate, model.AuditTargetUserService, userServiceTarget(newUserService),
+		serviceUserIDs(before), serviceUserIDs(after))
+	return nil
 }
 
 func (us *UserService) DeleteUserServices(ctx context.Context, usrservices []model.UserService) error {
-	return us.UserServiceRepo.DeleteUserServices(ctx, usrservices)
+	for _, userservice := range usrservices {
+		if err := us.DeleteUserService(ctx, userservice); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (us *UserService) AddUserServices(ctx context.Context, usrservices []model.UserService) error {
-	return us.UserServiceRepo.AddUserServices(ctx, usrservices)
+	for _, userservice := range usrservices {
+		if err := us.Add(ctx, userservice); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userServiceTarget names a mapping in the audit log as
+// "service_id:user_id".
+func userServiceTarget(userservice model.UserService) string {
+	return fmt.Sprintf("%d:%d", userservice.ServiceID, userservice.UserID)
+}
+
+func serviceUserIDs(userservices []model.UserService) interface{} {
+	userIDs := make([]int, len(userservices))
+	for i, userservice := range userservices {
+		userIDs[i] = userservice.UserID
+	}
+	return map[string][]int{"user_ids": userIDs}
 }
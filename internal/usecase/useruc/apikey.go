@@ -0,0 +1,131 @@
+package useruc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"monitoring/internal/model"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/usecase"
+	"time"
+)
+
+// APIKeyPrefix starts every key so they are easy to recognise, e.g. by
+// secret scanners.
+const APIKeyPrefix = "mon_"
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+type IAPIKeyUsecase interface {
+	Create(ctx context.Context, key model.APIKey) (secret string, err error)
+	List(ctx context.Context) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id int) error
+	Authenticate(ctx context.Context, secret string) (*model.Principal, error)
+}
+
+type APIKeyUC struct {
+	IAPIKeyRepo userrepo.IAPIKeyRepo
+	IUserRepo   userrepo.IUserRepo
+	// records issued and revoked keys when set
+	IAuditUC usecase.IAuditUsecase
+}
+
+// Create issues a key for key.Username and returns it, it can't be
+// recovered later.
+func (au *APIKeyUC) Create(ctx context.Context, key model.APIKey) (string, error) {
+	if key.Name == "" || key.Username == "" {
+		return "", errors.New("name and username must be filled")
+	}
+	if len(key.Scopes) == 0 {
+		return "", errors.New("at least one scope is required")
+	}
+	for _, scope := range key.Scopes {
+		if !scope.Valid() {
+			return "", fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return "", errors.New("expires_at must be in the future")
+	}
+
+	user, err := au.IUserRepo.Read(ctx, key.Username)
+	if err != nil {
+		return "", err
+	}
+	if user.UserId == 0 {
+		return "", errors.New("user not found")
+	}
+	key.UserID = user.UserId
+
+	random, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	secret := APIKeyPrefix + random
+	key.Prefix = secret[:len(APIKeyPrefix)+8]
+
+	if err := au.IAPIKeyRepo.Add(ctx, key, hashToken(secret)); err != nil {
+		return "", err
+	}
+	added, _, err := au.IAPIKeyRepo.GetByHash(ctx, hashToken(secret))
+	if err != nil {
+		usecase.AuditFailed(err, model.AuditCreate, model.AuditTargetAPIKey, key.Prefix)
+		return secret, nil
+	}
+	usecase.RecordAudit(ctx, au.IAuditUC, model.AuditCreate, model.AuditTargetAPIKey, added.Prefix, nil, added)
+	return secret, nil
+}
+
+func (au *APIKeyUC) List(ctx context.Context) ([]model.APIKey, error) {
+	return au.IAPIKeyRepo.List(ctx)
+}
+
+func (au *APIKeyUC) Revoke(ctx context.Context, id int) error {
+	before, _, err := au.IAPIKeyRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := au.IAPIKeyRepo.Revoke(ctx, id); err != nil {
+		return err
+	}
+	after := before
+	revokedAt := time.Now()
+	after.RevokedAt = &revokedAt
+	usecase.RecordAudit(ctx, au.IAuditUC, model.AuditUpdate, model.AuditTargetAPIKey, before.Prefix, before, after)
+	return nil
+}
+
+// Authenticate returns the principal of a key. It acts as its user with
+// the permissions both the key's scopes and the user's current role grant.
+func (au *APIKeyUC) Authenticate(ctx context.Context, secret string) (*model.Principal, error) {
+	key, found, err := au.IAPIKeyRepo.GetByHash(ctx, hashToken(secret))
+	if err != nil {
+		return nil, err
+	}
+	if !found || key.RevokedAt != nil || (key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := au.IUserRepo.Read(ctx, key.Username)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserId != key.UserID {
+		return nil, ErrInvalidAPIKey
+	}
+
+	principal := model.NewPrincipal(user.UserId, user.Username, model.AccessLevel(user.Role))
+	var permissions []model.Permission
+	for _, scope := range key.Scopes {
+		if principal.Can(scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+	principal.Permissions = permissions
+	principal.APIKeyID = key.ID
+
+	if err := au.IAPIKeyRepo.Touch(ctx, key.ID); err != nil {
+		return nil, err
+	}
+	return principal, nil
+}
@@ -10,6 +10,7 @@ type ILogin interface {
 }
 
 type LoginUC struct {
+	ID         int
 	Username   string
 	Role       int
 	ILoginRepo userrepo.ILoginRepo
@@ -23,6 +24,7 @@ func (l *LoginUC) Login(ctx context.Context, username, password string) (*LoginU
 	}
 
 	return &LoginUC{
+		ID:       user.ID,
 		Username: user.Username,
 		Role:     user.Role,
 	}, nil
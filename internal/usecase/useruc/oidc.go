@@ -0,0 +1,233 @@
+package useruc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"monitoring/internal/model"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/usecase"
+	hashPass "monitoring/pkg/hashPass"
+	"monitoring/pkg/oidc"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidOIDCLogin = errors.New("invalid oidc login")
+	// the account's claims map to no role and there's no default role
+	ErrOIDCAccessDenied = errors.New("no role is granted to this account")
+)
+
+type IOIDCUsecase interface {
+	AuthURL(ctx context.Context) (url, state, nonce string, err error)
+	Callback(ctx context.Context, code, nonce string) (model.TokenPair, error)
+}
+
+// OIDCUC logs users in through an OpenID Connect provider. Accounts are
+// linked to local users by subject, new ones get a user of the same name
+// unless that name is taken.
+type OIDCUC struct {
+	IOIDCRepo userrepo.IOIDCRepo
+	IUserRepo userrepo.IUserRepo
+	ITokenUC  ITokenUsecase
+	// records the users it creates and the roles it changes when set
+	IAuditUC usecase.IAuditUsecase
+
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// claims the username and the roles are read from
+	UsernameClaim string
+	RoleClaim     string
+	// role id of each RoleClaim value
+	RoleMapping map[string]int
+	// role of accounts no mapping matches, 0 denies them
+	DefaultRole int
+	HTTPClient  *http.Client
+
+	mu     sync.Mutex
+	client *oidc.Client
+}
+
+// ParseRoleMapping parses role mappings written as "value:roleid".
+func ParseRoleMapping(specs []string) (map[string]int, error) {
+	mapping := make(map[string]int, len(specs))
+	for _, spec := range specs {
+		i := strings.LastIndex(spec, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("role mapping %q: expected value:roleid", spec)
+		}
+		role, err := strconv.Atoi(spec[i+1:])
+		if err != nil || !validRole(role) {
+			return nil, fmt.Errorf("role mapping %q: unknown role", spec)
+		}
+		mapping[spec[:i]] = role
+	}
+	return mapping, nil
+}
+
+func (ou *OIDCUC) AuthURL(ctx context.Context) (string, string, string, error) {
+	client, err := ou.oidcClient(ctx)
+	if err != nil {
+		return "", "", "", err
+	}
+	state, err := randomToken(16)
+	if err != nil {
+		return "", "", "", err
+	}
+	nonce, err := randomToken(16)
+	if err != nil {
+		return "", "", "", err
+	}
+	return client.AuthCodeURL(state, nonce), state, nonce, nil
+}
+
+// Callback exchanges the code the provider redirected back with, then
+// provisions the user on its first login and issues the project's tokens.
+func (ou *OIDCUC) Callback(ctx context.Context, code, nonce string) (model.TokenPair, error) {
+	client, err := ou.oidcClient(ctx)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	token, err := client.Exchange(ctx, code)
+	if err != nil {
+		return model.TokenPair{}, fmt.Errorf("%w: %v", ErrInvalidOIDCLogin, err)
+	}
+	claims, err := client.VerifyIDToken(ctx, token.IDToken, nonce)
+	if err != nil {
+		return model.TokenPair{}, fmt.Errorf("%w: %v", ErrInvalidOIDCLogin, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+	username, _ := claims[ou.UsernameClaim].(string)
+	if subject == "" || username == "" {
+		return model.TokenPair{}, fmt.Errorf("%w: id token has no sub or %s claim", ErrInvalidOIDCLogin, ou.UsernameClaim)
+	}
+	role := ou.role(claims[ou.RoleClaim])
+	if role == 0 {
+		return model.TokenPair{}, ErrOIDCAccessDenied
+	}
+
+	user, err := ou.user(ctx, subject, username, role)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	// the provider decides the role, keep it in sync on every login
+	if user.Role != role {
+		if _, err := ou.IUserRepo.Update(ctx, user.Username, "", role); err != nil {
+			return model.TokenPair{}, err
+		}
+		before := user
+		user.Role = role
+		usecase.RecordAudit(ctx, ou.IAuditUC, model.AuditUpdate, model.AuditTargetUser, user.Username,
+			auditUser{UserRes: before}, auditUser{UserRes: user})
+	}
+	return ou.ITokenUC.Issue(ctx, user.UserId, user.Username, role)
+}
+
+// user returns the user linked to subject, creating it on the first login.
+// Existing local users are never linked by name, as the provider's username
+// claim isn't proof of owning them.
+func (ou *OIDCUC) user(ctx context.Context, subject, username string, role int) (model.UserRes, error) {
+	user, found, err := ou.IOIDCRepo.GetBySubject(ctx, subject)
+	if err != nil || found {
+		return user, err
+	}
+
+	user, err = ou.IUserRepo.Read(ctx, username)
+	if err != nil {
+		return user, err
+	}
+	if user.UserId != 0 {
+		return user, fmt.Errorf("%w: user %q already exists", ErrOIDCAccessDenied, username)
+	}
+
+	// the account logs in through the provider only
+	random, err := randomToken(32)
+	if err != nil {
+		return user, err
+	}
+	hash, err := hashPass.HashPassword(random)
+	if err != nil {
+		return user, err
+	}
+	created, err := ou.IUserRepo.Create(ctx, username, hash, role)
+	if err != nil {
+		return user, err
+	}
+	if !created {
+		return user, fmt.Errorf("%w: user %q already exists", ErrOIDCAccessDenied, username)
+	}
+	if user, err = ou.IUserRepo.Read(ctx, username); err != nil {
+		return user, err
+	}
+	if err := ou.IOIDCRepo.LinkSubject(ctx, user.UserId, subject); err != nil {
+		// don't leave an account behind that nobody can log in to, a
+		// concurrent first login may have linked the subject already
+		if delErr := ou.IUserRepo.Delete(ctx, username); delErr != nil {
+			return model.UserRes{}, fmt.Errorf("%w: %v, removing user %q: %v", ErrOIDCAccessDenied, err, username, delErr)
+		}
+		return model.UserRes{}, fmt.Errorf("%w: %v", ErrOIDCAccessDenied, err)
+	}
+	usecase.RecordAudit(ctx, ou.IAuditUC, model.AuditCreate, model.AuditTargetUser, username,
+		nil, auditUser{UserRes: user})
+	return user, nil
+}
+
+// role returns the most privileged role the claim values map to.
+func (ou *OIDCUC) role(claim interface{}) int {
+	var values []string
+	switch v := claim.(type) {
+	case string:
+		values = strings.Fields(v)
+	case []interface{}:
+		for _, value := range v {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	role := 0
+	for _, value := range values {
+		if r, ok := ou.RoleMapping[value]; ok && (role == 0 || r < role) {
+			role = r
+		}
+	}
+	if role == 0 && validRole(ou.DefaultRole) {
+		role = ou.DefaultRole
+	}
+	return role
+}
+
+// oidcClient discovers the provider on first use, so the api starts even
+// when it's unreachable.
+func (ou *OIDCUC) oidcClient(ctx context.Context) (*oidc.Client, error) {
+	ou.mu.Lock()
+	defer ou.mu.Unlock()
+
+	if ou.client != nil {
+		return ou.client, nil
+	}
+	httpClient := ou.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	provider, err := oidc.Discover(ctx, httpClient, ou.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	ou.client = oidc.NewClient(provider, ou.ClientID, ou.ClientSecret, ou.RedirectURL, ou.Scopes, httpClient)
+	return ou.client, nil
+}
+
+func validRole(role int) bool {
+	_, ok := model.RolePermissions[model.AccessLevel(role)]
+	return ok
+}
@@ -0,0 +1,58 @@
+package useruc
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"testing"
+)
+
+type fakeOIDC struct {
+	subjects map[string]int
+	// returned by LinkSubject when set
+	linkErr error
+}
+
+func (f *fakeOIDC) GetBySubject(ctx context.Context, subject string) (model.UserRes, bool, error) {
+	id, ok := f.subjects[subject]
+	return model.UserRes{UserId: id}, ok, nil
+}
+
+func (f *fakeOIDC) LinkSubject(ctx context.Context, userID int, subject string) error {
+	if f.linkErr != nil {
+		return f.linkErr
+	}
+	if _, ok := f.subjects[subject]; ok {
+		return errors.New("subject is linked to another user")
+	}
+	f.subjects[subject] = userID
+	return nil
+}
+
+func TestOIDCUserCreatedOnFirstLogin(t *testing.T) {
+	users := &fakeUsers{users: map[string]model.UserRes{}}
+	ou := &OIDCUC{IOIDCRepo: &fakeOIDC{subjects: map[string]int{}}, IUserRepo: users}
+
+	user, err := ou.user(context.Background(), "sub-1", "ann", int(model.Regular))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.UserId == 0 || user.Username != "ann" || users.users["ann"].UserId != user.UserId {
+		t.Errorf("user = %+v", user)
+	}
+}
+
+func TestOIDCUserRemovedWhenLinkFails(t *testing.T) {
+	users := &fakeUsers{users: map[string]model.UserRes{}}
+	// as if a concurrent first login linked the subject in the meantime
+	oidcRepo := &fakeOIDC{subjects: map[string]int{}, linkErr: errors.New("subject is linked to another user")}
+	ou := &OIDCUC{IOIDCRepo: oidcRepo, IUserRepo: users}
+
+	user, err := ou.user(context.Background(), "sub-1", "ann", int(model.Regular))
+	if !errors.Is(err, ErrOIDCAccessDenied) {
+		t.Fatalf("got %+v, %v, expected access denied", user, err)
+	}
+	if _, ok := users.users["ann"]; ok {
+		t.Error("user left behind without a linked subject")
+	}
+}
@@ -0,0 +1,202 @@
+package useruc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/pkg/jwtkeys"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+func init() {
+	// iat is compared against the time a user's tokens were revoked, with
+	// whole seconds a token issued right after a revocation would be
+	// revoked as well
+	jwt.TimePrecision = time.Microsecond
+}
+
+type ITokenUsecase interface {
+	Issue(ctx context.Context, userID int, username string, role int) (model.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (model.TokenPair, error)
+	Logout(ctx context.Context, claims *model.JwtCustomClaims, refreshToken string) error
+	RevokeUser(ctx context.Context, username string) error
+	IsRevoked(ctx context.Context, claims *model.JwtCustomClaims) (bool, error)
+}
+
+// TokenUC issues short-lived access tokens together with refresh tokens
+// that are rotated on every use.
+type TokenUC struct {
+	ITokenRepo userrepo.ITokenRepo
+	IUserRepo  userrepo.IUserRepo
+	Keys       *jwtkeys.KeySet
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+func (tu *TokenUC) Issue(ctx context.Context, userID int, username string, role int) (model.TokenPair, error) {
+	now := time.Now()
+	accessTTL := tu.accessTTL()
+
+	jti, err := randomToken(16)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	access, err := tu.Keys.Sign(&model.JwtCustomClaims{
+		Name:   username,
+		RoleId: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   strconv.Itoa(userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTTL)),
+		},
+	})
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	refresh, err := randomToken(32)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	err = tu.ITokenRepo.AddRefreshToken(ctx, model.RefreshToken{
+		UserID:    userID,
+		Username:  username,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(tu.refreshTTL()),
+	}, hashToken(refresh))
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	return model.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int64(accessTTL / time.Second),
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new pair. Presenting a token that
+// was already used revokes every token of its user, as it was likely stolen.
+func (tu *TokenUC) Refresh(ctx context.Context, refreshToken string) (model.TokenPair, error) {
+	token, found, err := tu.ITokenRepo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	if !found || time.Now().After(token.ExpiresAt) {
+		return model.TokenPair{}, ErrInvalidRefreshToken
+	}
+	if token.RevokedAt != nil {
+		if err := tu.RevokeUser(ctx, token.Username); err != nil {
+			return model.TokenPair{}, err
+		}
+		return model.TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	revoked, err := tu.ITokenRepo.RevokeRefreshToken(ctx, token.ID)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	if !revoked {
+		return model.TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	// the role may have changed and the user may be gone since
+	user, err := tu.IUserRepo.Read(ctx, token.Username)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	if user.UserId != token.UserID {
+		return model.TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	return tu.Issue(ctx, user.UserId, user.Username, user.Role)
+}
+
+// Logout revokes the access token of claims and the given refresh token, or
+// every refresh token of the user when none is given.
+func (tu *TokenUC) Logout(ctx context.Context, claims *model.JwtCustomClaims, refreshToken string) error {
+	if claims.ID != "" {
+		expiresAt := time.Now().Add(tu.accessTTL())
+		if claims.ExpiresAt != nil {
+			expiresAt = claims.ExpiresAt.Time
+		}
+		if err := tu.ITokenRepo.RevokeAccessToken(ctx, claims.ID, expiresAt); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return tu.ITokenRepo.RevokeUserRefreshTokens(ctx, claims.Name)
+	}
+	token, found, err := tu.ITokenRepo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	if !found || token.Username != claims.Name {
+		return ErrInvalidRefreshToken
+	}
+	_, err = tu.ITokenRepo.RevokeRefreshToken(ctx, token.ID)
+	return err
+}
+
+// RevokeUser cuts off every token issued to the user so far.
+func (tu *TokenUC) RevokeUser(ctx context.Context, username string) error {
+	if err := tu.ITokenRepo.RevokeUserRefreshTokens(ctx, username); err != nil {
+		return err
+	}
+	// tokens made by MakeToken can outlive access tokens, keep the entry as
+	// long as the longest lived credential
+	now := time.Now().Truncate(jwt.TimePrecision)
+	return tu.ITokenRepo.RevokeUserAccessTokens(ctx, username, now, now.Add(tu.refreshTTL()))
+}
+
+func (tu *TokenUC) IsRevoked(ctx context.Context, claims *model.JwtCustomClaims) (bool, error) {
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	return tu.ITokenRepo.IsRevoked(ctx, claims.ID, claims.Name, issuedAt)
+}
+
+func (tu *TokenUC) accessTTL() time.Duration {
+	if tu.AccessTTL <= 0 {
+		return DefaultAccessTTL
+	}
+	return tu.AccessTTL
+}
+
+func (tu *TokenUC) refreshTTL() time.Duration {
+	if tu.RefreshTTL <= 0 {
+		return DefaultRefreshTTL
+	}
+	return tu.RefreshTTL
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
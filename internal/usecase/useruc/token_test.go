@@ -0,0 +1,318 @@
+package useruc
+
+import (
+	"context"
+	"errors"
+	"monitoring/internal/model"
+	"monitoring/internal/repository/userrepo"
+	"monitoring/pkg/jwtkeys"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type revokedEntry struct {
+	jti      string
+	username string
+	before   time.Time
+}
+
+// fakeTokens keeps tokens in memory with the semantics of TokenRepo.
+type fakeTokens struct {
+	refresh map[string]*model.RefreshToken
+	revoked []revokedEntry
+	nextID  int64
+}
+
+func newFakeTokens() *fakeTokens {
+	return &fakeTokens{refresh: map[string]*model.RefreshToken{}}
+}
+
+func (f *fakeTokens) AddRefreshToken(ctx context.Context, token model.RefreshToken, hash string) error {
+	f.nextID++
+	token.ID = f.nextID
+	f.refresh[hash] = &token
+	return nil
+}
+
+func (f *fakeTokens) GetRefreshToken(ctx context.Context, hash string) (model.RefreshToken, bool, error) {
+	token, ok := f.refresh[hash]
+	if !ok {
+		return model.RefreshToken{}, false, nil
+	}
+	return *token, true, nil
+}
+
+func (f *fakeTokens) RevokeRefreshToken(ctx context.Context, id int64) (bool, error) {
+	for _, token := range f.refresh {
+		if token.ID == id && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeTokens) RevokeUserRefreshTokens(ctx context.Context, username string) error {
+	now := time.Now()
+	for _, token := range f.refresh {
+		if token.Username == username && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (f *fakeTokens) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	f.revoked = append(f.revoked, revokedEntry{jti: jti})
+	return nil
+}
+
+func (f *fakeTokens) RevokeUserAccessTokens(ctx context.Context, username string, before, expiresAt time.Time) error {
+	f.revoked = append(f.revoked, revokedEntry{username: username, before: before})
+	return nil
+}
+
+func (f *fakeTokens) IsRevoked(ctx context.Context, jti, username string, issuedAt time.Time) (bool, error) {
+	for _, r := range f.revoked {
+		if jti != "" && r.jti == jti || r.username == username && r.before.After(issuedAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeUsers struct {
+	userrepo.IUserRepo
+	users   map[string]model.UserRes
+	updates int
+}
+
+func (f *fakeUsers) Read(ctx context.Context, username string) (model.UserRes, error) {
+	return f.users[username], nil
+}
+
+func (f *fakeUsers) Create(ctx context.Context, username, hashPass string, role int) (bool, error) {
+	if _, ok := f.users[username]; ok {
+		return false, nil
+	}
+	f.users[username] = model.UserRes{UserId: len(f.users) + 1, Username: username, Role: role}
+	return true, nil
+}
+
+func (f *fakeUsers) Delete(ctx context.Context, username string) error {
+	delete(f.users, username)
+	return nil
+}
+
+func (f *fakeUsers) Update(ctx context.Context, username, hashPass string, role int) (bool, error) {
+	f.updates++
+	user := f.users[username]
+	if role != 0 {
+		user.Role = role
+	}
+	f.users[username] = user
+	return true, nil
+}
+
+func newTokenUC(t *testing.T) (*TokenUC, *fakeTokens) {
+	t.Helper()
+	keys, err := jwtkeys.Ephemeral()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := newFakeTokens()
+	return &TokenUC{
+		ITokenRepo: tokens,
+		IUserRepo:  &fakeUsers{users: map[string]model.UserRes{"ann": {UserId: 1, Username: "ann", Role: 2}}},
+		Keys:       keys,
+	}, tokens
+}
+
+func claimsOf(t *testing.T, tu *TokenUC, access string) *model.JwtCustomClaims {
+	t.Helper()
+	claims := &model.JwtCustomClaims{}
+	if _, err := jwt.ParseWithClaims(access, claims, tu.Keys.Keyfunc); err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+func TestRefreshRotates(t *testing.T) {
+	ctx := context.Background()
+	tu, _ := newTokenUC(t)
+
+	first, err := tu.Issue(ctx, 1, "ann", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := tu.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == first.AccessToken {
+		t.Fatal("refresh returned the same tokens")
+	}
+	if claims := claimsOf(t, tu, second.AccessToken); claims.Name != "ann" || claims.RoleId != 2 {
+		t.Errorf("claims = %+v", claims)
+	}
+	third, err := tu.Refresh(ctx, second.RefreshToken)
+	if err != nil {
+		t.Fatalf("rotated token: %v", err)
+	}
+
+	if _, err := tu.Refresh(ctx, "unknown"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("unknown token: got %v", err)
+	}
+
+	// reusing the first token means it leaked, the whole chain goes
+	if _, err := tu.Refresh(ctx, first.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("reused token: got %v", err)
+	}
+	if _, err := tu.Refresh(ctx, third.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("latest token after reuse: got %v", err)
+	}
+	revoked, err := tu.IsRevoked(ctx, claimsOf(t, tu, third.AccessToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Error("access token still valid after reuse")
+	}
+}
+
+func TestRefreshExpired(t *testing.T) {
+	ctx := context.Background()
+	tu, _ := newTokenUC(t)
+	tu.RefreshTTL = time.Nanosecond
+
+	pair, err := tu.Issue(ctx, 1, "ann", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := tu.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestRevokeUser(t *testing.T) {
+	ctx := context.Background()
+	tu, _ := newTokenUC(t)
+
+	before, err := tu.Issue(ctx, 1, "ann", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := tu.Issue(ctx, 2, "bob", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tu.RevokeUser(ctx, "ann"); err != nil {
+		t.Fatal(err)
+	}
+	// a moment later, well within the second iat used to be truncated to
+	time.Sleep(time.Millisecond)
+	after, err := tu.Issue(ctx, 1, "ann", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{name: "issued before", token: before.AccessToken, want: true},
+		{name: "issued after", token: after.AccessToken, want: false},
+		{name: "other user", token: other.AccessToken, want: false},
+	}
+	for _, tt := range tests {
+		revoked, err := tu.IsRevoked(ctx, claimsOf(t, tu, tt.token))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if revoked != tt.want {
+			t.Errorf("%s: revoked = %v, want %v", tt.name, revoked, tt.want)
+		}
+	}
+
+	if _, err := tu.Refresh(ctx, after.RefreshToken); err != nil {
+		t.Errorf("refresh token issued after: %v", err)
+	}
+	if _, err := tu.Refresh(ctx, before.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("refresh token issued before: got %v", err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	ctx := context.Background()
+	tu, _ := newTokenUC(t)
+
+	pair, err := tu.Issue(ctx, 1, "ann", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := tu.Issue(ctx, 1, "ann", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := claimsOf(t, tu, pair.AccessToken)
+	if err := tu.Logout(ctx, claims, pair.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	if revoked, _ := tu.IsRevoked(ctx, claims); !revoked {
+		t.Error("access token valid after logout")
+	}
+	if revoked, _ := tu.IsRevoked(ctx, claimsOf(t, tu, other.AccessToken)); revoked {
+		t.Error("logout revoked another session")
+	}
+	if _, err := tu.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("refresh after logout: got %v", err)
+	}
+	if err := tu.Logout(ctx, &model.JwtCustomClaims{Name: "bob"}, other.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("logout with another user's token: got %v", err)
+	}
+}
+
+type fakeTokenUC struct {
+	ITokenUsecase
+	revoked []string
+}
+
+func (f *fakeTokenUC) RevokeUser(ctx context.Context, username string) error {
+	f.revoked = append(f.revoked, username)
+	return nil
+}
+
+func TestUpdateRevokesTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		role     int
+		revoke   bool
+	}{
+		{name: "password", password: "secret", revoke: true},
+		{name: "role", role: 1, revoke: true},
+		{name: "same role", role: 2},
+		{name: "nothing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &fakeTokenUC{}
+			uu := &UserUsecase{
+				IUserRepo: &fakeUsers{users: map[string]model.UserRes{"ann": {UserId: 1, Username: "ann", Role: 2}}},
+				ITokenUC:  tokens,
+			}
+			if _, err := uu.Update(context.Background(), "ann", tt.password, tt.role); err != nil {
+				t.Fatal(err)
+			}
+			if revoked := len(tokens.revoked) > 0; revoked != tt.revoke {
+				t.Errorf("revoked = %v, want %v", revoked, tt.revoke)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"monitoring/internal/model"
 	"monitoring/internal/repository/userrepo"
+	"monitoring/internal/usecase"
 	hashPass "monitoring/pkg/hashPass"
 )
 
@@ -18,6 +19,18 @@ type IUserUsecase interface {
 
 type UserUsecase struct {
 	IUserRepo userrepo.IUserRepo
+	// revokes the tokens of deleted users and of users whose password or
+	// role changed when set
+	ITokenUC ITokenUsecase
+	// records changes when set
+	IAuditUC usecase.IAuditUsecase
+}
+
+// auditUser is a user as the audit log shows it, passwords are only marked
+// as changed.
+type auditUser struct {
+	model.UserRes
+	PasswordChanged bool `json:"password_changed,omitempty"`
 }
 
 func (ruu *UserUsecase) Create(ctx context.Context, username, password string, role int) (ok bool, err error) {
@@ -30,7 +43,17 @@ func (ruu *UserUsecase) Create(ctx context.Context, username, password string, r
 	if err != nil {
 		return false, err
 	}
-	return ok, err
+	if !ok {
+		return ok, nil
+	}
+	user, err := ruu.IUserRepo.Read(ctx, username)
+	if err != nil {
+		usecase.AuditFailed(err, model.AuditCreate, model.AuditTargetUser, username)
+		return ok, nil
+	}
+	usecase.RecordAudit(ctx, ruu.IAuditUC, model.AuditCreate, model.AuditTargetUser, username,
+		nil, auditUser{UserRes: user})
+	return ok, nil
 }
 
 func (ruu *UserUsecase) Update(ctx context.Context, username, password string, role int) (ok bool, err error) {
@@ -42,18 +65,49 @@ func (ruu *UserUsecase) Update(ctx context.Context, username, password string, r
 			return false, err
 		}
 	}
+	before, err := ruu.IUserRepo.Read(ctx, username)
+	if err != nil {
+		return false, err
+	}
 	ok, err = ruu.IUserRepo.Update(ctx, username, hashpass, role)
 	if err != nil {
 		return false, err
 	}
-	return ok, err
+	// tokens carry the role and were issued against the old password
+	if ok && ruu.ITokenUC != nil && (hashpass != "" || role != 0 && role != before.Role) {
+		if err := ruu.ITokenUC.RevokeUser(ctx, username); err != nil {
+			return ok, err
+		}
+	}
+	after, err := ruu.IUserRepo.Read(ctx, username)
+	if err != nil {
+		usecase.AuditFailed(err, model.AuditUpdate, model.AuditTargetUser, username)
+		return ok, nil
+	}
+	usecase.RecordAudit(ctx, ruu.IAuditUC, model.AuditUpdate, model.AuditTargetUser, username,
+		auditUser{UserRes: before}, auditUser{UserRes: after, PasswordChanged: hashpass != ""})
+	return ok, nil
 }
 
 func (ruu *UserUsecase) Delete(ctx context.Context, username string) error {
-	err := ruu.IUserRepo.Delete(ctx, username)
+	before, err := ruu.IUserRepo.Read(ctx, username)
 	if err != nil {
 		return err
 	}
+	err = ruu.IUserRepo.Delete(ctx, username)
+	if err != nil {
+		return err
+	}
+	// cut the deleted user off before anything else can fail
+	if ruu.ITokenUC != nil {
+		if err := ruu.ITokenUC.RevokeUser(ctx, username); err != nil {
+			return err
+		}
+	}
+	if before.UserId != 0 {
+		usecase.RecordAudit(ctx, ruu.IAuditUC, model.AuditDelete, model.AuditTargetUser, username,
+			auditUser{UserRes: before}, nil)
+	}
 	return nil
 }
 
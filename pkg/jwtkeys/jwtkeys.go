@@ -0,0 +1,196 @@
+// Package jwtkeys holds the keys tokens are signed and verified with. Every
+// key has an id that is written to the kid header of the tokens it signs,
+// so old keys can keep verifying tokens while a new key signs.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	// nil for keys that only verify
+	SignKey   interface{}
+	VerifyKey interface{}
+}
+
+type KeySet struct {
+	keys   map[string]Key
+	active string
+}
+
+// New returns a key set signing with the key given by active.
+func New(active string, keys ...Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]Key, len(keys)), active: active}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("jwtkeys: key without id")
+		}
+		if _, ok := ks.keys[key.ID]; ok {
+			return nil, fmt.Errorf("jwtkeys: duplicate key id %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	signer, ok := ks.keys[active]
+	if !ok {
+		return nil, fmt.Errorf("jwtkeys: active key %q not found", active)
+	}
+	if signer.SignKey == nil {
+		return nil, fmt.Errorf("jwtkeys: active key %q can't sign", active)
+	}
+	return ks, nil
+}
+
+// Ephemeral returns a key set with a random HS256 key, tokens signed with it
+// become invalid when the process exits.
+func Ephemeral() (*KeySet, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(secret[:4])
+	return New(id, Key{ID: id, Method: jwt.SigningMethodHS256, SignKey: secret, VerifyKey: secret})
+}
+
+// Parse reads keys from specs of the form "kid:alg:value". The value of an
+// HS256 key is the secret itself, the value of an RS256 or EdDSA key is the
+// path of a PEM file holding the private key, or only the public key for
+// keys that are kept to verify older tokens.
+func Parse(active string, specs []string) (*KeySet, error) {
+	keys := make([]Key, 0, len(specs))
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("jwtkeys: key %q must be kid:alg:value", spec)
+		}
+		key, err := LoadKey(parts[0], parts[1], parts[2])
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return New(active, keys...)
+}
+
+// LoadKey builds a key of the given algorithm, see Parse for value.
+func LoadKey(id, alg, value string) (Key, error) {
+	key := Key{ID: id}
+	switch alg {
+	case HS256:
+		if value == "" {
+			return Key{}, fmt.Errorf("jwtkeys: key %q has an empty secret", id)
+		}
+		key.Method = jwt.SigningMethodHS256
+		key.SignKey = []byte(value)
+		key.VerifyKey = []byte(value)
+		return key, nil
+	case RS256:
+		key.Method = jwt.SigningMethodRS256
+	case EdDSA:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return Key{}, fmt.Errorf("jwtkeys: key %q has unsupported algorithm %q", id, alg)
+	}
+
+	data, err := os.ReadFile(value)
+	if err != nil {
+		return Key{}, fmt.Errorf("jwtkeys: key %q: %w", id, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("jwtkeys: key %q: no PEM data in %s", id, value)
+	}
+
+	if strings.Contains(block.Type, "PUBLIC KEY") {
+		pub, err := parsePublicKey(block)
+		if err != nil {
+			return Key{}, fmt.Errorf("jwtkeys: key %q: %w", id, err)
+		}
+		key.VerifyKey = pub
+	} else {
+		priv, err := parsePrivateKey(block)
+		if err != nil {
+			return Key{}, fmt.Errorf("jwtkeys: key %q: %w", id, err)
+		}
+		key.SignKey = priv
+		key.VerifyKey = priv.(crypto.Signer).Public()
+	}
+
+	if err := checkKeyType(key); err != nil {
+		return Key{}, fmt.Errorf("jwtkeys: key %q: %w", id, err)
+	}
+	return key, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.PrivateKey, error) {
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
+
+func parsePublicKey(block *pem.Block) (crypto.PublicKey, error) {
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func checkKeyType(key Key) error {
+	switch key.VerifyKey.(type) {
+	case *rsa.PublicKey:
+		if key.Method == jwt.SigningMethodRS256 {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if key.Method == jwt.SigningMethodEdDSA {
+			return nil
+		}
+	}
+	return fmt.Errorf("%T can't be used for %s", key.VerifyKey, key.Method.Alg())
+}
+
+// Sign signs claims with the active key.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	key := ks.keys[ks.active]
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.SignKey)
+}
+
+// Keyfunc returns the verification key of the kid header of token, tokens
+// without one are checked against the active key.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = ks.active
+	}
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.VerifyKey, nil
+}
@@ -0,0 +1,197 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writePEM writes block to a file in a temporary directory and returns its
+// path together with the encoded bytes.
+func writePEM(t *testing.T, name, blockType string, der []byte) (string, []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path, data
+}
+
+func rsaKey(t *testing.T) (private, public string, publicPEM []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, _ = writePEM(t, "key.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, publicPEM = writePEM(t, "key.pub", "PUBLIC KEY", der)
+	return private, public, publicPEM
+}
+
+func claims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{Subject: "admin", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+}
+
+func verify(ks *KeySet, token string) error {
+	_, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, ks.Keyfunc)
+	return err
+}
+
+func TestSignAndVerify(t *testing.T) {
+	private, _, _ := rsaKey(t)
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(edPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPath, _ := writePEM(t, "ed.pem", "PRIVATE KEY", der)
+
+	for _, spec := range []string{"h:HS256:secret", "r:RS256:" + private, "e:EdDSA:" + edPath} {
+		kid := spec[:1]
+		ks, err := Parse(kid, []string{spec})
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+		token, err := ks.Sign(claims())
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+		if err := verify(ks, token); err != nil {
+			t.Errorf("%s: %v", spec, err)
+		}
+	}
+}
+
+func TestAlgorithmConfusionIsRejected(t *testing.T) {
+	private, _, publicPEM := rsaKey(t)
+	ks, err := Parse("rsa", []string{"rsa:RS256:" + private})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an HS256 token using the public key, which anyone can read, as secret
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	forged.Header["kid"] = "rsa"
+	token, err := forged.SignedString(publicPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(ks, token); err == nil {
+		t.Fatal("HS256 token signed with the RSA public key was accepted")
+	}
+
+	// same without a kid, checked against the active key
+	delete(forged.Header, "kid")
+	token, err = forged.SignedString(publicPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(ks, token); err == nil {
+		t.Fatal("HS256 token without kid signed with the RSA public key was accepted")
+	}
+}
+
+func TestUnknownKeyIDIsRejected(t *testing.T) {
+	ks, err := Parse("a", []string{"a:HS256:secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := Parse("b", []string{"b:HS256:secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := other.Sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(ks, token); err == nil || !strings.Contains(err.Error(), `unknown key id "b"`) {
+		t.Fatalf("got %v, expected unknown key id", err)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	oldPrivate, oldPublic, _ := rsaKey(t)
+	newPrivate, _, _ := rsaKey(t)
+
+	before, err := Parse("2024-01", []string{"2024-01:RS256:" + oldPrivate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldToken, err := before.Sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the old key is kept with only its public half to verify older tokens
+	after, err := Parse("2024-02", []string{"2024-01:RS256:" + oldPublic, "2024-02:RS256:" + newPrivate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(after, oldToken); err != nil {
+		t.Errorf("token of the old key: %v", err)
+	}
+
+	newToken, err := after.Sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := parsed.Header["kid"]; kid != "2024-02" {
+		t.Errorf("signed with kid %v, want the active key", kid)
+	}
+	if err := verify(after, newToken); err != nil {
+		t.Errorf("token of the new key: %v", err)
+	}
+	if err := verify(before, newToken); err == nil {
+		t.Error("token of the new key verified by a set without it")
+	}
+
+	// a key that only verifies can't become the active one
+	if _, err := Parse("2024-01", []string{"2024-01:RS256:" + oldPublic}); err == nil {
+		t.Error("public key accepted as the active key")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	private, _, _ := rsaKey(t)
+
+	tests := []struct {
+		name   string
+		active string
+		specs  []string
+		err    string
+	}{
+		{name: "malformed", active: "a", specs: []string{"a:HS256"}, err: "must be kid:alg:value"},
+		{name: "empty secret", active: "a", specs: []string{"a:HS256:"}, err: "empty secret"},
+		{name: "unsupported algorithm", active: "a", specs: []string{"a:HS512:secret"}, err: "unsupported algorithm"},
+		{name: "duplicate id", active: "a", specs: []string{"a:HS256:x", "a:HS256:y"}, err: "duplicate key id"},
+		{name: "missing active", active: "b", specs: []string{"a:HS256:x"}, err: `active key "b" not found`},
+		{name: "missing file", active: "a", specs: []string{"a:RS256:/nonexistent.pem"}, err: "no such file"},
+		{name: "key of the wrong type", active: "a", specs: []string{"a:EdDSA:" + private}, err: "can't be used for EdDSA"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.active, tt.specs); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: got %v, expected %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testClientID = "monitoring"
+
+// testProvider serves a discovery document and a key set holding key under
+// kid "k1".
+func testProvider(t *testing.T, key *rsa.PublicKey) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Provider{
+			Issuer:                srv.URL,
+			AuthorizationEndpoint: srv.URL + "/authorize",
+			TokenEndpoint:         srv.URL + "/token",
+			JWKSURI:               srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []jwk{{
+				Kty: "RSA",
+				Kid: "k1",
+				Use: "sig",
+				Alg: "RS256",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	return srv
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	raw, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := testProvider(t, &key.PublicKey)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	provider, err := Discover(ctx, srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+
+	claims := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":   srv.URL,
+			"sub":   "user-1",
+			"aud":   testClientID,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": "n-1",
+		}
+		if change != nil {
+			change(c)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   string
+	}{
+		{
+			name:  "valid",
+			token: sign(t, jwt.SigningMethodRS256, key, "k1", claims(nil)),
+		},
+		{
+			name:  "bad audience",
+			token: sign(t, jwt.SigningMethodRS256, key, "k1", claims(func(c jwt.MapClaims) { c["aud"] = "other" })),
+			err:   "audience",
+		},
+		{
+			name:  "bad issuer",
+			token: sign(t, jwt.SigningMethodRS256, key, "k1", claims(func(c jwt.MapClaims) { c["iss"] = "https://evil.example" })),
+			err:   "issuer",
+		},
+		{
+			name:  "bad nonce",
+			token: sign(t, jwt.SigningMethodRS256, key, "k1", claims(func(c jwt.MapClaims) { c["nonce"] = "n-2" })),
+			err:   "nonce",
+		},
+		{
+			name:  "no nonce",
+			token: sign(t, jwt.SigningMethodRS256, key, "k1", claims(func(c jwt.MapClaims) { delete(c, "nonce") })),
+			err:   "nonce",
+		},
+		{
+			name:  "other authorized party",
+			token: sign(t, jwt.SigningMethodRS256, key, "k1", claims(func(c jwt.MapClaims) { c["azp"] = "other" })),
+			err:   "another client",
+		},
+		{
+			name:  "expired",
+			token: sign(t, jwt.SigningMethodRS256, key, "k1", claims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() })),
+			err:   "expired",
+		},
+		{
+			name:  "no expiry",
+			token: sign(t, jwt.SigningMethodRS256, key, "k1", claims(func(c jwt.MapClaims) { delete(c, "exp") })),
+			err:   "exp",
+		},
+		{
+			// the public key used as an HMAC secret
+			name:  "hs256",
+			token: sign(t, jwt.SigningMethodHS256, key.PublicKey.N.Bytes(), "k1", claims(nil)),
+			err:   "signing method",
+		},
+		{
+			name:  "none",
+			token: sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "k1", claims(nil)),
+			err:   "signing method",
+		},
+		{
+			name:  "unknown kid",
+			token: sign(t, jwt.SigningMethodRS256, key, "k2", claims(nil)),
+			err:   "unknown key id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(provider, testClientID, "secret", srv.URL+"/callback", nil, srv.Client())
+			got, err := client.VerifyIDToken(ctx, tt.token, "n-1")
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got["sub"] != "user-1" {
+					t.Errorf("sub = %v", got["sub"])
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("got error %v, expected one mentioning %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenOtherKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := testProvider(t, &key.PublicKey)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	provider, err := Discover(ctx, srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	client := NewClient(provider, testClientID, "secret", srv.URL+"/callback", nil, srv.Client())
+
+	token := sign(t, jwt.SigningMethodRS256, other, "k1", jwt.MapClaims{
+		"iss":   srv.URL,
+		"sub":   "user-1",
+		"aud":   testClientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": "n-1",
+	})
+	if _, err := client.VerifyIDToken(ctx, token, "n-1"); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Fatalf("got error %v, expected a signature error", err)
+	}
+}